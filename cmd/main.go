@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
 	_ "time/tzdata"
 
 	"github.com/spf13/cobra"
@@ -62,7 +63,30 @@ func main() {
 				return fmt.Errorf("unknown value [%s] for `output-multiline` supplied, supported: [preserved, squashed, indented]", outputHandlingStr)
 			}
 
-			p := parser.NewParser(f, out, parser.WithMaxFunctionCount(maxFunctions), parser.WithMultilineOutputHandling(outputHandling))
+			varsList, err := cmd.Flags().GetStringArray("var")
+			if err != nil {
+				return fmt.Errorf("failed to read var flag: %w", err)
+			}
+			vars := make(map[string]string, len(varsList))
+			for _, v := range varsList {
+				name, value, found := strings.Cut(v, "=")
+				if !found || name == "" {
+					return fmt.Errorf("invalid value [%s] for `var` supplied, expected format: name=value", v)
+				}
+				vars[name] = value
+			}
+
+			includeRoot, err := cmd.Flags().GetString("include-root")
+			if err != nil {
+				return fmt.Errorf("failed to read include-root flag: %w", err)
+			}
+
+			p := parser.NewParser(f, out,
+				parser.WithMaxFunctionCount(maxFunctions),
+				parser.WithMultilineOutputHandling(outputHandling),
+				parser.WithVariables(vars),
+				parser.WithIncludeRoot(includeRoot),
+			)
 			return p.Parse(cmd.Context())
 		},
 	}
@@ -70,6 +94,8 @@ func main() {
 	cmd.Flags().StringP("output-file", "f", "", "path to the file where result will be saved to, if not set, stdOut is used")
 	cmd.Flags().Int("max-functions", 200, "max amount of function calls that may occur during parsing of the provided file")
 	cmd.Flags().StringP("output-multiline", "o", "indented", "Sets how multiline output of functions will be formatted. Options: `preserved`, `squashed`, `indented`")
+	cmd.Flags().StringArray("var", nil, "variable in format `name=value` which will be available in parsed file, may be used multiple times")
+	cmd.Flags().String("include-root", "", "`directory` which files may be included from using include and import functions, if not set, including is disabled")
 
 	if err := cmd.Execute(); err != nil {
 		metaErr := new(metaError.MetaError)
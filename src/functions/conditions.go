@@ -0,0 +1,186 @@
+package functions
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	valueTrue  = "true"
+	valueFalse = "false"
+)
+
+// returns second parameter if condition (first parameter) is true, third parameter (optional, empty by default) otherwise
+// only the returned parameter is evaluated
+func (f Functions) ifElse(param ...LazyParam) (string, error) {
+	if len(param) != 2 && len(param) != 3 {
+		return "", fmt.Errorf("invalid parameter count, 2 or 3 expected %d provided", len(param))
+	}
+	cond, err := param[0].Value()
+	if err != nil {
+		return "", err
+	}
+	if isTrue(cond) {
+		return param[1].Value()
+	}
+	if len(param) == 3 {
+		return param[2].Value()
+	}
+	return "", nil
+}
+
+// returns true if all parameters are true, evaluation stops with the first false parameter
+func (f Functions) and(param ...LazyParam) (string, error) {
+	if len(param) == 0 {
+		return "", fmt.Errorf("invalid parameter count, at least 1 expected %d provided", len(param))
+	}
+	for _, p := range param {
+		value, err := p.Value()
+		if err != nil {
+			return "", err
+		}
+		if !isTrue(value) {
+			return valueFalse, nil
+		}
+	}
+	return valueTrue, nil
+}
+
+// returns true if any of the parameters is true, evaluation stops with the first true parameter
+func (f Functions) or(param ...LazyParam) (string, error) {
+	if len(param) == 0 {
+		return "", fmt.Errorf("invalid parameter count, at least 1 expected %d provided", len(param))
+	}
+	for _, p := range param {
+		value, err := p.Value()
+		if err != nil {
+			return "", err
+		}
+		if isTrue(value) {
+			return valueTrue, nil
+		}
+	}
+	return valueFalse, nil
+}
+
+// returns true if variable of provided name (passed either as variable reference or a string) exists
+func (f Functions) isSet(param ...LazyParam) (string, error) {
+	if len(param) != 1 {
+		return "", fmt.Errorf("invalid parameter count, 1 expected %d provided", len(param))
+	}
+	name, err := variableName(param[0])
+	if err != nil {
+		return "", err
+	}
+	_, found := f.values[name]
+	return strconv.FormatBool(found), nil
+}
+
+// returns value of variable of provided name (passed either as variable reference or a string),
+// if the variable does not exist, fallback (second parameter) is evaluated and returned
+func (f Functions) getVarOr(param ...LazyParam) (string, error) {
+	if len(param) != 2 {
+		return "", fmt.Errorf("invalid parameter count, 2 expected %d provided", len(param))
+	}
+	name, err := variableName(param[0])
+	if err != nil {
+		return "", err
+	}
+	if value, found := f.values[name]; found {
+		return value, nil
+	}
+	return param[1].Value()
+}
+
+// returns name of the referenced variable or value of the parameter if it is not a variable reference
+func variableName(param LazyParam) (string, error) {
+	if param.Variable != "" {
+		return param.Variable, nil
+	}
+	return param.Value()
+}
+
+// returns true if first parameter is false and vice versa
+func (f Functions) not(param ...string) (string, error) {
+	if err := paramCountCheck(1, len(param)); err != nil {
+		return "", err
+	}
+	return strconv.FormatBool(!isTrue(param[0])), nil
+}
+
+// returns true if both parameters are equal
+func (f Functions) eq(param ...string) (string, error) {
+	if err := paramCountCheck(2, len(param)); err != nil {
+		return "", err
+	}
+	return strconv.FormatBool(param[0] == param[1]), nil
+}
+
+// returns true if parameters are NOT equal
+func (f Functions) ne(param ...string) (string, error) {
+	if err := paramCountCheck(2, len(param)); err != nil {
+		return "", err
+	}
+	return strconv.FormatBool(param[0] != param[1]), nil
+}
+
+// returns true if first parameter is lower than the second one
+// parameters are compared as numbers if both are numeric, otherwise they are compared as strings
+func (f Functions) lt(param ...string) (string, error) {
+	if err := paramCountCheck(2, len(param)); err != nil {
+		return "", err
+	}
+	return strconv.FormatBool(compareValues(param[0], param[1]) < 0), nil
+}
+
+// returns true if first parameter is greater than the second one
+// parameters are compared as numbers if both are numeric, otherwise they are compared as strings
+func (f Functions) gt(param ...string) (string, error) {
+	if err := paramCountCheck(2, len(param)); err != nil {
+		return "", err
+	}
+	return strconv.FormatBool(compareValues(param[0], param[1]) > 0), nil
+}
+
+// returns true if first parameter contains the second one
+func (f Functions) contains(param ...string) (string, error) {
+	if err := paramCountCheck(2, len(param)); err != nil {
+		return "", err
+	}
+	return strconv.FormatBool(strings.Contains(param[0], param[1])), nil
+}
+
+// returns true if first parameter matches regular expression (second parameter)
+func (f Functions) matches(param ...string) (string, error) {
+	if err := paramCountCheck(2, len(param)); err != nil {
+		return "", err
+	}
+	re, err := regexp.Compile(param[1])
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatBool(re.MatchString(param[0])), nil
+}
+
+// returns whether value is considered to be true, empty string, 0 and false (case-insensitive) are false
+func isTrue(value string) bool {
+	return value != "" && value != "0" && !strings.EqualFold(value, valueFalse)
+}
+
+// compares values as numbers if both are numeric, otherwise as strings
+func compareValues(a, b string) int {
+	numA, errA := strconv.ParseFloat(a, 64)
+	numB, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case numA < numB:
+			return -1
+		case numA > numB:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,133 @@
+package functions
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zeropsio/zParser/v2/src/util"
+)
+
+const (
+	cronHourly = "hourly"
+	cronDaily  = "daily"
+	cronWeekly = "weekly"
+)
+
+// cronWindow represents hours [start, end) in which the job may be scheduled, end may be lower than start
+// when the window spans over midnight
+type cronWindow struct {
+	start int
+	end   int
+}
+
+// returns cron expression with random minute (and hour/weekday based on period) inside of an optional window
+// randomCron(period, [window])
+func (f Functions) randomCron(param ...string) (string, error) {
+	if len(param) != 1 && len(param) != 2 {
+		return "", fmt.Errorf("invalid parameter count, 1 or 2 expected %d provided", len(param))
+	}
+	return cronExpression(util.RandInt, param[0], param[1:]...)
+}
+
+// returns cron expression the same way as randomCron, but the slot is derived from hash of seed,
+// so the same seed always produces the same expression
+// cronSpread(seed, period, [window])
+func (f Functions) cronSpread(param ...string) (string, error) {
+	if len(param) != 2 && len(param) != 3 {
+		return "", fmt.Errorf("invalid parameter count, 2 or 3 expected %d provided", len(param))
+	}
+	sum := sha256.Sum256([]byte(param[0]))
+	offset := 0
+	// consumes 8 bytes of the hash for each pick, 3 picks at most are needed (minute, hour, weekday)
+	pick := func(n int) (int, error) {
+		v := binary.BigEndian.Uint64(sum[offset : offset+8])
+		offset += 8
+		return int(v % uint64(n)), nil
+	}
+	return cronExpression(pick, param[1], param[2:]...)
+}
+
+// builds cron expression for period, pick returns number in range [0, n)
+func cronExpression(pick func(n int) (int, error), period string, window ...string) (string, error) {
+	w := cronWindow{start: 0, end: 24}
+	if len(window) > 0 {
+		var err error
+		if w, err = parseCronWindow(window[0]); err != nil {
+			return "", err
+		}
+	}
+
+	minute, err := pick(60)
+	if err != nil {
+		return "", err
+	}
+
+	switch period {
+	case cronHourly:
+		return fmt.Sprintf("%d %s * * *", minute, w.cronField()), nil
+	case cronDaily, cronWeekly:
+		hourIdx, err := pick(w.len())
+		if err != nil {
+			return "", err
+		}
+		hour := (w.start + hourIdx) % 24
+		if period == cronDaily {
+			return fmt.Sprintf("%d %d * * *", minute, hour), nil
+		}
+		weekday, err := pick(7)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d %d * * %d", minute, hour, weekday), nil
+	}
+	return "", fmt.Errorf("unsupported period [%s], supported: [%s, %s, %s]", period, cronHourly, cronDaily, cronWeekly)
+}
+
+// parses window in format start-end (e.g. 1-5 or 22-4), where end hour is exclusive
+func parseCronWindow(in string) (cronWindow, error) {
+	startStr, endStr, found := strings.Cut(in, "-")
+	if !found {
+		return cronWindow{}, fmt.Errorf("invalid window [%s], expected format start-end (e.g. 1-5)", in)
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(startStr))
+	if err != nil {
+		return cronWindow{}, fmt.Errorf("invalid window [%s] start: %w", in, err)
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(endStr))
+	if err != nil {
+		return cronWindow{}, fmt.Errorf("invalid window [%s] end: %w", in, err)
+	}
+	if start < 0 || start > 23 || end < 1 || end > 24 {
+		return cronWindow{}, fmt.Errorf("invalid window [%s], start must be between 0 and 23, end between 1 and 24", in)
+	}
+	if start == end {
+		return cronWindow{}, fmt.Errorf("invalid window [%s], start and end must differ", in)
+	}
+	return cronWindow{start: start, end: end}, nil
+}
+
+// returns number of hours in the window
+func (w cronWindow) len() int {
+	if w.end > w.start {
+		return w.end - w.start
+	}
+	return 24 - w.start + w.end
+}
+
+// returns cron hour field matching all hours of the window
+func (w cronWindow) cronField() string {
+	switch {
+	case w.start == 0 && w.end == 24:
+		return "*"
+	case w.len() == 1:
+		return strconv.Itoa(w.start)
+	case w.end > w.start:
+		return fmt.Sprintf("%d-%d", w.start, w.end-1)
+	case w.end == 1:
+		return fmt.Sprintf("%d-23,0", w.start)
+	}
+	return fmt.Sprintf("%d-23,0-%d", w.start, w.end-1)
+}
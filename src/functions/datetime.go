@@ -0,0 +1,224 @@
+package functions
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/bykof/gostradamus"
+)
+
+// datetime format presets, which may be used instead of gostradamus format tokens
+const (
+	formatRFC3339     = "RFC3339"
+	formatRFC3339Nano = "RFC3339Nano"
+	formatRFC1123     = "RFC1123"
+	formatISOWeek     = "ISOWeek"
+	formatUnix        = "unix"
+	formatUnixMilli   = "unixMilli"
+)
+
+// offsetComponentRegexp matches one component of date offset, see parseDateOffset
+var offsetComponentRegexp = regexp.MustCompile(`^([+-]?)(\d+)(mo|y|w|d|h|m|s)`)
+
+// clock provides current time used by all functions, it is shared by copies of Functions, so it can be replaced
+type clock struct {
+	now func() time.Time
+}
+
+// SetClock sets function returning current time, which is used instead of time.Now (e.g. to get reproducible output).
+func (f Functions) SetClock(now func() time.Time) {
+	f.clock.now = now
+}
+
+func (f Functions) now() time.Time {
+	return f.clock.now()
+}
+
+// dateOffset represents offset of a date, calendar parts are kept separately, because their length varies
+type dateOffset struct {
+	years    int
+	months   int
+	days     int
+	duration time.Duration
+}
+
+// returns current unix timestamp (in seconds) optionally shifted by offset (first parameter)
+func (f Functions) unixTimestamp(param ...string) (string, error) {
+	if len(param) > 1 {
+		return "", fmt.Errorf("invalid parameter count, 0 or 1 expected %d provided", len(param))
+	}
+	now := f.now()
+	if len(param) == 1 {
+		offset, err := parseDateOffset(param[0])
+		if err != nil {
+			return "", err
+		}
+		now = offset.apply(now)
+	}
+	return strconv.FormatInt(now.Unix(), 10), nil
+}
+
+// parses datetime value (first parameter) using input format (second parameter) and formats it
+// using output format (third parameter), optionally in timezone (fourth parameter) and shifted by offset (fifth parameter)
+// timezone is also used for values which do not contain timezone information, UTC is used by default
+func (f Functions) parseDatetime(param ...string) (string, error) {
+	if len(param) < 3 || len(param) > 5 {
+		return "", fmt.Errorf("invalid parameter count, 3 to 5 expected %d provided", len(param))
+	}
+	location := time.UTC
+	if len(param) >= 4 {
+		var err error
+		if location, err = gostradamus.LoadLocation(param[3]); err != nil {
+			return "", err
+		}
+	}
+	t, err := parseDatetimeValue(param[0], param[1], location)
+	if err != nil {
+		return "", err
+	}
+	if len(param) == 5 {
+		offset, err := parseDateOffset(param[4])
+		if err != nil {
+			return "", err
+		}
+		t = offset.apply(t)
+	}
+	return formatDatetime(t.In(location), param[2]), nil
+}
+
+// returns amount of seconds in provided duration (first parameter) in the offset format, e.g. 90d or 1h30m
+func (f Functions) durationSeconds(param ...string) (string, error) {
+	if err := paramCountCheck(1, len(param)); err != nil {
+		return "", err
+	}
+	offset, err := parseDateOffset(param[0])
+	if err != nil {
+		return "", err
+	}
+	if offset.years != 0 || offset.months != 0 {
+		return "", fmt.Errorf("duration [%s] must not contain years or months, because their length varies", param[0])
+	}
+	seconds := int64(offset.days)*int64(24*time.Hour/time.Second) + int64(offset.duration/time.Second)
+	return strconv.FormatInt(seconds, 10), nil
+}
+
+// returns go time layout of a preset, if the format is one of the presets backed by standard layouts
+func goLayout(format string) (string, bool) {
+	switch format {
+	case formatRFC3339:
+		return time.RFC3339, true
+	case formatRFC3339Nano:
+		return time.RFC3339Nano, true
+	case formatRFC1123:
+		return time.RFC1123, true
+	}
+	return "", false
+}
+
+// formats time using one of the presets or gostradamus.FormatToken values
+func formatDatetime(t time.Time, format string) string {
+	if layout, ok := goLayout(format); ok {
+		return t.Format(layout)
+	}
+	switch format {
+	case formatISOWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case formatUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case formatUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	}
+	return gostradamus.DateTimeFromTime(t).Format(format)
+}
+
+// parses time using one of the presets or gostradamus.FormatToken values
+func parseDatetimeValue(value, format string, location *time.Location) (time.Time, error) {
+	if layout, ok := goLayout(format); ok {
+		return time.ParseInLocation(layout, value, location)
+	}
+	switch format {
+	case formatISOWeek:
+		var year, week int
+		if _, err := fmt.Sscanf(value, "%04d-W%02d", &year, &week); err != nil {
+			return time.Time{}, fmt.Errorf("invalid ISO week [%s], expected format YYYY-Www: %w", value, err)
+		}
+		if week < 1 || week > 53 {
+			return time.Time{}, fmt.Errorf("invalid ISO week [%s], week must be between 1 and 53", value)
+		}
+		// 4th of January is always in the first ISO week, returned time is Monday of the requested week
+		jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, location)
+		monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+		return monday.AddDate(0, 0, (week-1)*7), nil
+	case formatUnix, formatUnixMilli:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if format == formatUnixMilli {
+			return time.UnixMilli(n), nil
+		}
+		return time.Unix(n, 0), nil
+	}
+	dt, err := gostradamus.ParseInTimezone(value, format, gostradamus.Timezone(location.String()))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return dt.Time(), nil
+}
+
+// parses offset comprised of components with units y (years), mo (months), w (weeks), d (days), h (hours),
+// m (minutes) and s (seconds), e.g. +30d, -1h30m or +1y-1d; component without a sign uses sign of the previous one
+func parseDateOffset(in string) (dateOffset, error) {
+	if in == "" {
+		return dateOffset{}, errors.New("empty offset")
+	}
+	offset := dateOffset{}
+	sign := 1
+	rest := in
+	for rest != "" {
+		match := offsetComponentRegexp.FindStringSubmatch(rest)
+		if match == nil {
+			return dateOffset{}, fmt.Errorf("invalid offset [%s], expected components like +30d, -1h30m or +1y2mo", in)
+		}
+		rest = rest[len(match[0]):]
+
+		switch match[1] {
+		case "+":
+			sign = 1
+		case "-":
+			sign = -1
+		}
+		n, err := strconv.Atoi(match[2])
+		if err != nil {
+			return dateOffset{}, fmt.Errorf("invalid offset [%s]: %w", in, err)
+		}
+		n *= sign
+
+		switch match[3] {
+		case "y":
+			offset.years += n
+		case "mo":
+			offset.months += n
+		case "w":
+			offset.days += n * 7
+		case "d":
+			offset.days += n
+		case "h":
+			offset.duration += time.Duration(n) * time.Hour
+		case "m":
+			offset.duration += time.Duration(n) * time.Minute
+		case "s":
+			offset.duration += time.Duration(n) * time.Second
+		}
+	}
+	return offset, nil
+}
+
+// returns t shifted by the offset
+func (o dateOffset) apply(t time.Time) time.Time {
+	return t.AddDate(o.years, o.months, o.days).Add(o.duration)
+}
@@ -0,0 +1,145 @@
+package functions
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	cryptoRand "crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/zeropsio/zParser/v2/src/util"
+)
+
+// encrypts plaintext (second parameter) for the owner of the public key (first parameter), output is base64 encoded
+//   - X25519 (base64) and ED25519 (PEM or ssh) keys produce NaCl sealed box (libsodium's crypto_box_seal)
+//   - RSA (PEM or ssh) keys produce RSA-OAEP with SHA-256
+func (f Functions) encryptFor(param ...string) (string, error) {
+	if err := paramCountCheck(2, len(param)); err != nil {
+		return "", err
+	}
+	plaintext := []byte(param[1])
+
+	publicKey, err := parseEncryptionPublicKey(param[0])
+	if err != nil {
+		return "", err
+	}
+
+	var ciphertext []byte
+	switch key := publicKey.(type) {
+	case *[32]byte:
+		ciphertext, err = box.SealAnonymous(nil, plaintext, key, cryptoRand.Reader)
+	case *rsa.PublicKey:
+		ciphertext, err = rsa.EncryptOAEP(sha256.New(), cryptoRand.Reader, key, plaintext, nil)
+	default:
+		return "", fmt.Errorf("unsupported public key type [%T]", publicKey)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// encrypts plaintext (second parameter) using AES-GCM with the key (first parameter)
+// output is base64 encoded nonce followed by ciphertext
+func (f Functions) encryptAESGCM(param ...string) (string, error) {
+	if err := paramCountCheck(2, len(param)); err != nil {
+		return "", err
+	}
+	key, err := parseAESKey(param[0])
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := cryptoRand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(param[1]), nil)), nil
+}
+
+// parses public key used for encryption, returned key is either *[32]byte (X25519) or *rsa.PublicKey
+func parseEncryptionPublicKey(in string) (any, error) {
+	in = strings.TrimSpace(in)
+	if !strings.HasPrefix(in, "-----BEGIN") && !strings.HasPrefix(in, "ssh-") {
+		raw, err := base64.StdEncoding.DecodeString(in)
+		if err != nil || len(raw) != 32 {
+			return nil, errors.New("unsupported public key, expected PEM, ssh or base64 encoded X25519 key")
+		}
+		return (*[32]byte)(raw), nil
+	}
+
+	publicKey, err := parsePublicKey(in)
+	if err != nil {
+		return nil, err
+	}
+	switch key := publicKey.(type) {
+	case ed25519.PublicKey:
+		x25519Key, err := util.ED25519PublicKeyToX25519(key)
+		if err != nil {
+			return nil, err
+		}
+		return (*[32]byte)(x25519Key), nil
+	case *rsa.PublicKey:
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported public key type [%T]", publicKey)
+}
+
+// parses PEM (PKIX) or ssh (authorized keys format) encoded public key
+func parsePublicKey(in string) (any, error) {
+	in = strings.TrimSpace(in)
+	if strings.HasPrefix(in, "ssh-") {
+		sshKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(in))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ssh public key: %w", err)
+		}
+		cryptoKey, ok := sshKey.(ssh.CryptoPublicKey)
+		if !ok {
+			return nil, errors.New("unsupported ssh public key")
+		}
+		return cryptoKey.CryptoPublicKey(), nil
+	}
+
+	block, _ := pem.Decode([]byte(in))
+	if block == nil || block.Type != typePublicKey {
+		return nil, errors.New("failed to decode PEM block containing public key")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// key is decoded from hex if possible, otherwise it is decoded from base64 if possible,
+// otherwise it is used as is if it is 16, 24 or 32 bytes long
+func parseAESKey(in string) ([]byte, error) {
+	validLen := func(l int) bool {
+		return l == 16 || l == 24 || l == 32
+	}
+	if key, err := hex.DecodeString(in); err == nil && validLen(len(key)) {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(in); err == nil && validLen(len(key)) {
+		return key, nil
+	}
+	if validLen(len(in)) {
+		return []byte(in), nil
+	}
+	return nil, errors.New("invalid AES key, expected 16, 24 or 32 bytes (raw, hex or base64 encoded)")
+}
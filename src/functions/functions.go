@@ -2,26 +2,44 @@ package functions
 
 import (
 	"crypto/ed25519"
+	"crypto/hmac"
 	cryptoRand "crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
-	mathRand "math/rand"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bykof/gostradamus"
 	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/hkdf"
 	"golang.org/x/crypto/ssh"
 
 	"github.com/zeropsio/zParser/v2/src/util"
 )
 
-const maxRandBytesLen = 1024
+const (
+	maxRandBytesLen = 1024
+	maxBcryptCost   = 14 // limit accepted by verifyPassword, so a crafted hash cannot exhaust the parser service
+)
+
+// supported encodings of binary output
+const (
+	encodingHex       = "hex"
+	encodingBase64    = "base64"
+	encodingBase64Url = "base64url"
+	encodingString    = "string" // same character set as generateRandomString
+)
 
 // constants for SSH keys
 const (
@@ -32,43 +50,145 @@ const (
 	suffixPrivate         = "Private"
 	suffixPublicSsh       = "PublicSsh"
 	suffixPrivateSsh      = "PrivateSsh"
+	suffixPresharedKey    = "PresharedKey"
 )
 
 type function func(param ...string) (string, error)
 
 type Functions struct {
-	values    map[string]string
-	functions map[string]function
+	values        map[string]string
+	functions     map[string]registered[function]
+	lazyFunctions map[string]registered[lazyFunction]
+	macros        map[string]macro
+	clock         *clock
+	issued        map[string]map[string]struct{} // values of a kind which must not repeat during one parse
+	template      *templateState
 }
 
 func NewFunctions(valueStore map[string]string) *Functions {
 	f := &Functions{
-		values: valueStore,
-	}
-	f.functions = map[string]function{
-		"generateRandomInt":       f.generateRandomInt,
-		"generateRandomBytes":     f.generateRandomBytes,
-		"generateRandomString":    f.generateRandomString,
-		"generateRandomStringVar": f.generateRandomStringVar,
-		"pickRandom":              f.pickRandom,
-		"mercuryInRetrograde":     f.mercuryInRetrograde,
-		"getDatetime":             f.getDatetime,
-		"setVar":                  f.setVar,
-		"getVar":                  f.getVar,
-		"generateED25519Key":      f.generateED25519Key,
-		"generateRSA2048Key":      f.generateRSA2048Key,
-		"generateRSA4096Key":      f.generateRSA4096Key,
-		"generateJWT":             f.generateJWT,
+		values:   valueStore,
+		macros:   map[string]macro{},
+		clock:    &clock{now: time.Now},
+		issued:   map[string]map[string]struct{}{},
+		template: &templateState{},
+	}
+	f.functions = map[string]registered[function]{
+		"generateRandomInt":       {f.generateRandomInt, takes(required("min", typeInt), required("max", typeInt))},
+		"generateRandomBytes":     {f.generateRandomBytes, takes(required("length", typeInt))},
+		"generateRandomString":    {f.generateRandomString, takes(required("length", typeInt))},
+		"generateRandomStringVar": {f.generateRandomStringVar, takes(text("name"), required("length", typeInt))},
+		"getDatetime":             {f.getDatetime, takes(text("format"), optional("timezone", typeString, "UTC"), optional("offset", typeString, ""))},
+		"setVar":                  {f.setVar, takes(text("name"), text("content"))},
+		"getVar":                  {f.getVar, takes(text("name"))},
+		"generateED25519Key":      {f.generateED25519Key, takes(text("name"))},
+		"generateRSA2048Key":      {f.generateRSA2048Key, takes(text("name"))},
+		"generateRSA4096Key":      {f.generateRSA4096Key, takes(text("name"))},
+		"generateJWT":             {f.generateJWT, takes(text("tokenSecret"), text("jsonPayload"))},
+		"hmac":                    {f.hmac, takes(text("algorithm"), text("key"), text("message"), optional("encoding", typeString, encodingHex))},
+		"verifyPassword":          {f.verifyPassword, takes(text("hash"), text("plain"))},
+		"deriveSecret":            {f.deriveSecret, takes(text("master"), text("label"), required("length", typeInt), text("encoding"))},
+		"encryptFor":              {f.encryptFor, takes(text("publicKey"), text("plaintext"))},
+		"encryptAESGCM":           {f.encryptAESGCM, takes(text("key"), text("plaintext"))},
+		"sign":                    {f.sign, takes(text("privateKey"), text("message"), text("encoding"))},
+		"verifySignature":         {f.verifySignature, takes(text("publicKey"), text("message"), text("signature"))},
+		"generateWireGuardKey":    {f.generateWireGuardKey, takes(text("name"))},
+		"wireGuardInterface":      {f.wireGuardInterface, takes(text("name"), text("address"), optional("listenPort", typeInt, ""))},
+		"wireGuardPeer": {f.wireGuardPeer, takes(
+			text("name"),
+			text("allowedIPs"),
+			optional("endpoint", typeString, ""),
+			optional("presharedKey", typeString, ""),
+		)},
+		"generateTOTPSecret": {f.generateTOTPSecret, takes(text("name"), text("issuer"), text("account"))},
+		"totpCode":           {f.totpCode, takes(text("secret"))},
+		"generateUUID":       {f.generateUUID, takes(text("version"))},
+		"generateUUIDVar":    {f.generateUUIDVar, takes(text("name"), text("version"))},
+		"generateULID":       {f.generateULID, takes()},
+		"generateULIDVar":    {f.generateULIDVar, takes(text("name"))},
+		"generateNanoID": {f.generateNanoID, takes(
+			optional("length", typeInt, strconv.Itoa(nanoIDDefaultLength)),
+			optional("alphabet", typeString, nanoIDAlphabet),
+		)},
+		"generateNanoIDVar": {f.generateNanoIDVar, takes(
+			text("name"),
+			optional("length", typeInt, strconv.Itoa(nanoIDDefaultLength)),
+			optional("alphabet", typeString, nanoIDAlphabet),
+		)},
+		"generateKSUID":    {f.generateKSUID, takes()},
+		"generateKSUIDVar": {f.generateKSUIDVar, takes(text("name"))},
+		"generatePassphrase": {f.generatePassphrase, takes(
+			required("words", typeInt),
+			optional("separator", typeString, "-"),
+			optional("wordlist", typeString, wordlistLarge),
+		)},
+		"generatePassphraseVar": {f.generatePassphraseVar, takes(
+			text("name"),
+			required("words", typeInt),
+			optional("separator", typeString, "-"),
+			optional("wordlist", typeString, wordlistLarge),
+		)},
+		"generateHostname":   {f.generateHostname, takes(optional("prefix", typeString, ""), required("length", typeInt))},
+		"generateIdentifier": {f.generateIdentifier, takes(text("style"), optional("length", typeInt, strconv.Itoa(defaultIdentifierLength)))},
+		"randomCron":         {f.randomCron, takes(text("period"), optional("window", typeString, ""))},
+		"cronSpread":         {f.cronSpread, takes(text("seed"), text("period"), optional("window", typeString, ""))},
+		"randomPort":         {f.randomPort, takes(required("min", typeInt), required("max", typeInt))},
+		"randomIPInCIDR":     {f.randomIPInCIDR, takes(text("cidr"))},
+		"randomULAPrefix":    {f.randomULAPrefix, takes()},
+		"randomMAC":          {f.randomMAC, takes(optional("locallyAdministered", typeBool, "true"))},
+		"shuffle":            {f.shuffle, nil},
+		"unixTimestamp":      {f.unixTimestamp, takes(optional("offset", typeString, ""))},
+		"parseDatetime": {f.parseDatetime, takes(
+			text("value"),
+			text("inputFormat"),
+			text("outputFormat"),
+			optional("timezone", typeString, "UTC"),
+			optional("offset", typeString, ""),
+		)},
+		"durationSeconds": {f.durationSeconds, takes(text("duration"))},
+		"not":             {f.not, takes(text("condition"))},
+		"eq":              {f.eq, takes(text("a"), text("b"))},
+		"ne":              {f.ne, takes(text("a"), text("b"))},
+		"lt":              {f.lt, takes(text("a"), text("b"))},
+		"gt":              {f.gt, takes(text("a"), text("b"))},
+		"contains":        {f.contains, takes(text("a"), text("b"))},
+		"matches":         {f.matches, takes(text("a"), text("regexp"))},
+		"include":         {f.include, takes(text("path"))},
+		"import":          {f.importFile, takes(text("path"))},
+		"render":          {f.render, takes(text("template"))},
+	}
+	f.lazyFunctions = map[string]registered[lazyFunction]{
+		"pickRandom":          {f.pickRandom, nil},
+		"pickWeighted":        {f.pickWeighted, nil},
+		"sample":              {f.sample, nil},
+		"pickByHash":          {f.pickByHash, nil},
+		"mercuryInRetrograde": {f.mercuryInRetrograde, takes(text("contentIfYes"), text("contentIfNo"))},
+		"if":                  {f.ifElse, takes(text("condition"), text("then"), optional("else", typeString, ""))},
+		"and":                 {f.and, nil},
+		"or":                  {f.or, nil},
+		"isSet":               {f.isSet, takes(text("name"))},
+		"getVarOr":            {f.getVarOr, takes(text("name"), text("fallback"))},
+		"repeat":              {f.repeat, takes(required("n", typeInt), text("template"), optional("indexVar", typeString, defaultIndexVar))},
+		"each": {f.each, takes(
+			text("list"),
+			text("itemVar"),
+			text("template"),
+			optional("separator", typeString, defaultListSep),
+		)},
+		"define": {f.define, nil},
 	}
 	return f
 }
 
 func (f Functions) Call(name string, params ...string) (string, error) {
-	fn, found := f.functions[name]
+	r, found := f.functions[name]
 	if !found {
+		if f.IsLazy(name) {
+			return f.CallLazy(name, valuesToLazyParams(params)...)
+		}
 		return "", fmt.Errorf("function [%s] not found", name)
 	}
-	return fn(params...)
+	return r.fn(params...)
 }
 
 // generates cryptographically secure random int in [min, max]
@@ -124,31 +244,47 @@ func (f Functions) generateRandomString(param ...string) (string, error) {
 	return util.BytesToString([]byte(bytes)), nil
 }
 
-// selects one random value from all provided parameters
-func (f Functions) pickRandom(param ...string) (string, error) {
+// selects one random value from all provided parameters, only the selected parameter is evaluated
+func (f Functions) pickRandom(param ...LazyParam) (string, error) {
 	if len(param) == 0 {
 		return "", fmt.Errorf("invalid parameter count, at least 1 expected %d provided", len(param))
 	}
-	return param[mathRand.Intn(len(param))], nil
+	idx, err := util.RandInt(len(param))
+	if err != nil {
+		return "", err
+	}
+	return param[idx].Value()
 }
 
 // returns date time using formatted by format inside first parameter which supports gostradamus.FormatToken values
+// and presets (RFC3339, RFC3339Nano, RFC1123, ISOWeek, unix, unixMilli)
 // if second parameter is provided, it is used as a timezone, otherwise UTC is assumed
+// if third parameter is provided, current time is shifted by it (e.g. +30d or -1h30m)
 func (f Functions) getDatetime(param ...string) (string, error) {
-	if len(param) == 1 {
-		return gostradamus.UTCNow().Format(param[0]), nil
+	if len(param) < 1 || len(param) > 3 {
+		return "", fmt.Errorf("invalid parameter count, 1 to 3 expected %d provided", len(param))
+	}
+	now := f.now()
+	if len(param) == 3 {
+		offset, err := parseDateOffset(param[2])
+		if err != nil {
+			return "", err
+		}
+		now = offset.apply(now)
 	}
-	if len(param) == 2 {
-		if _, err := gostradamus.LoadLocation(param[1]); err != nil {
+	location := time.UTC
+	if len(param) >= 2 {
+		var err error
+		if location, err = gostradamus.LoadLocation(param[1]); err != nil {
 			return "", err
 		}
-		return gostradamus.NowInTimezone(gostradamus.Timezone(param[1])).Format(param[0]), nil
 	}
-	return "", fmt.Errorf("invalid parameter count, at 1 or 2 expected %d provided", len(param))
+	return formatDatetime(now.In(location), param[0]), nil
 }
 
 // returns first parameter if Mercury is in retrograde and second parameter if it is NOT in retrograde
-func (f Functions) mercuryInRetrograde(param ...string) (string, error) {
+// only the returned parameter is evaluated
+func (f Functions) mercuryInRetrograde(param ...LazyParam) (string, error) {
 	if err := paramCountCheck(2, len(param)); err != nil {
 		return "", err
 	}
@@ -158,9 +294,9 @@ func (f Functions) mercuryInRetrograde(param ...string) (string, error) {
 		return "", err
 	}
 	if yes {
-		return param[0], nil
+		return param[0].Value()
 	}
-	return param[1], nil
+	return param[1].Value()
 }
 
 func (f Functions) generateRandomStringVar(param ...string) (string, error) {
@@ -287,7 +423,7 @@ func (f Functions) generateJWT(param ...string) (string, error) {
 
 	var payload = jwt.MapClaims{
 		"iss": "zerops",
-		"iat": time.Now().Unix(),
+		"iat": f.now().Unix(),
 	}
 	if err := json.NewDecoder(strings.NewReader(param[1])).Decode(&payload); err != nil {
 		return "", fmt.Errorf("failed to decode provided JSON payload: %w", err)
@@ -301,9 +437,116 @@ func (f Functions) generateJWT(param ...string) (string, error) {
 	return tokenString, nil
 }
 
+// computes HMAC of the message (third parameter) using hash algorithm (first parameter) and key (second parameter)
+// result is encoded using encoding from optional fourth parameter, hex is used by default
+func (f Functions) hmac(param ...string) (string, error) {
+	if len(param) != 3 && len(param) != 4 {
+		return "", fmt.Errorf("invalid parameter count, 3 or 4 expected %d provided", len(param))
+	}
+	hashFunc, err := util.HashFunc(param[0])
+	if err != nil {
+		return "", err
+	}
+	encoding := encodingHex
+	if len(param) == 4 {
+		encoding = param[3]
+	}
+
+	mac := hmac.New(hashFunc, []byte(param[1]))
+	mac.Write([]byte(param[2]))
+	return encodeBytes(mac.Sum(nil), encoding)
+}
+
+// verifies password hash (first parameter) matches plain password (second parameter), returns an empty string on success
+// supports bcrypt, argon2id, scrypt (PHC format) and pbkdf2_sha256 (Django format) hashes
+func (f Functions) verifyPassword(param ...string) (string, error) {
+	if err := paramCountCheck(2, len(param)); err != nil {
+		return "", err
+	}
+	hash, plain := param[0], param[1]
+
+	var err error
+	switch {
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		var cost int
+		if cost, err = bcrypt.Cost([]byte(hash)); err == nil {
+			if cost > maxBcryptCost {
+				return "", fmt.Errorf("invalid hash, cost [%d] must be at most [%d]", cost, maxBcryptCost)
+			}
+			err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain))
+		}
+	case strings.HasPrefix(hash, "$argon2id$"):
+		err = util.Argon2IDPasswordVerify(hash, plain)
+	case strings.HasPrefix(hash, "$scrypt$"):
+		err = util.ScryptPasswordVerify(hash, plain)
+	case strings.HasPrefix(hash, "pbkdf2_sha256$"):
+		err = util.PBKDF2SHA256PasswordVerify(hash, plain)
+	default:
+		return "", errors.New("unsupported password hash, supported: [bcrypt, argon2id, scrypt, pbkdf2_sha256]")
+	}
+	if err != nil {
+		return "", fmt.Errorf("password verification failed: %w", err)
+	}
+	return "", nil
+}
+
+// derives secret from master secret (first parameter) and label (second parameter) using HKDF-SHA256
+// same master secret and label always result in the same secret of requested length (third parameter) in bytes,
+// which is encoded using encoding from fourth parameter
+func (f Functions) deriveSecret(param ...string) (string, error) {
+	if err := paramCountCheck(4, len(param)); err != nil {
+		return "", err
+	}
+	if param[0] == "" {
+		return "", errors.New("master secret must not be empty")
+	}
+	length, err := strconv.ParseInt(param[2], 10, 64)
+	if err != nil {
+		return "", err
+	}
+	if length < 1 || length > maxRandBytesLen {
+		return "", fmt.Errorf("provided length %d must be between 1 and %d bytes", length, maxRandBytesLen)
+	}
+
+	secret := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(param[0]), nil, []byte(param[1])), secret); err != nil {
+		return "", err
+	}
+	return encodeBytes(secret, param[3])
+}
+
 func paramCountCheck(expected, received int) error {
 	if expected != received {
 		return fmt.Errorf("invalid parameter count, %d expected %d provided", expected, received)
 	}
 	return nil
 }
+
+// encodes bytes using one of supported encodings
+func encodeBytes(in []byte, encoding string) (string, error) {
+	switch encoding {
+	case encodingHex:
+		return hex.EncodeToString(in), nil
+	case encodingBase64:
+		return base64.StdEncoding.EncodeToString(in), nil
+	case encodingBase64Url:
+		return base64.RawURLEncoding.EncodeToString(in), nil
+	case encodingString:
+		return util.BytesToString(in), nil
+	}
+	return "", fmt.Errorf("unsupported encoding [%s], supported: [%s, %s, %s, %s]", encoding, encodingHex, encodingBase64, encodingBase64Url, encodingString)
+}
+
+// decodes bytes encoded using hex, base64 or base64url encoding (tried in this order)
+func decodeBytes(in string) ([]byte, error) {
+	if out, err := hex.DecodeString(in); err == nil {
+		return out, nil
+	}
+	if out, err := base64.StdEncoding.DecodeString(in); err == nil {
+		return out, nil
+	}
+	if out, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(in, "=")); err == nil {
+		return out, nil
+	}
+	return nil, errors.New("unsupported encoding, supported: [hex, base64, base64url]")
+}
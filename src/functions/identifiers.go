@@ -0,0 +1,119 @@
+package functions
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zeropsio/zParser/v2/src/util"
+)
+
+const (
+	lowerLetters = "abcdefghijklmnopqrstuvwxyz"
+	upperLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digits       = "0123456789"
+
+	maxHostnameLen          = 25 // max length of Zerops service hostname
+	defaultIdentifierLength = 16
+)
+
+// hostnamePrefixRegexp matches valid prefix of Zerops service hostname (or empty prefix)
+var hostnamePrefixRegexp = regexp.MustCompile(`^([a-z][a-z0-9]*)?$`)
+
+// identifierStyle describes character set and length limits of identifiers valid in different contexts
+type identifierStyle struct {
+	first  string // characters allowed as the first character
+	rest   string // characters allowed after the first character
+	minLen int
+	maxLen int
+}
+
+func getIdentifierStyle(name string) (identifierStyle, error) {
+	switch name {
+	case "dns":
+		return identifierStyle{first: lowerLetters, rest: lowerLetters + digits, minLen: 1, maxLen: 63}, nil
+	case "env":
+		return identifierStyle{first: upperLetters, rest: upperLetters + digits + "_", minLen: 1, maxLen: 255}, nil
+	case "database":
+		return identifierStyle{first: lowerLetters, rest: lowerLetters + digits + "_", minLen: 1, maxLen: 63}, nil
+	case "s3":
+		return identifierStyle{first: lowerLetters, rest: lowerLetters + digits, minLen: 3, maxLen: 63}, nil
+	}
+	return identifierStyle{}, fmt.Errorf("unsupported identifier style [%s], supported: [dns, env, database, s3]", name)
+}
+
+// generates hostname of total length (last parameter) comprised of lowercase alphanumeric characters
+// starting with a letter and prefixed by prefix (first parameter, optional)
+func (f Functions) generateHostname(param ...string) (string, error) {
+	if len(param) != 1 && len(param) != 2 {
+		return "", fmt.Errorf("invalid parameter count, 1 or 2 expected %d provided", len(param))
+	}
+	var prefix string
+	if len(param) == 2 {
+		prefix = param[0]
+	}
+	length, err := strconv.Atoi(param[len(param)-1])
+	if err != nil {
+		return "", err
+	}
+	if !hostnamePrefixRegexp.MatchString(prefix) {
+		return "", fmt.Errorf("invalid prefix [%s], only lowercase alphanumeric characters starting with a letter are allowed", prefix)
+	}
+	if length < 1 || length > maxHostnameLen {
+		return "", fmt.Errorf("provided length %d must be between 1 and %d", length, maxHostnameLen)
+	}
+	if len(prefix) > length {
+		return "", fmt.Errorf("prefix [%s] is longer than requested length %d", prefix, length)
+	}
+
+	first := lowerLetters
+	if prefix != "" {
+		first = lowerLetters + digits
+	}
+	random, err := randomIdentifier(first, lowerLetters+digits, length-len(prefix))
+	if err != nil {
+		return "", err
+	}
+	return prefix + random, nil
+}
+
+// generates identifier valid in context given by style (first parameter) of length (second parameter, optional, 16 by default)
+func (f Functions) generateIdentifier(param ...string) (string, error) {
+	if len(param) != 1 && len(param) != 2 {
+		return "", fmt.Errorf("invalid parameter count, 1 or 2 expected %d provided", len(param))
+	}
+	style, err := getIdentifierStyle(param[0])
+	if err != nil {
+		return "", err
+	}
+	length := defaultIdentifierLength
+	if len(param) == 2 {
+		length, err = strconv.Atoi(param[1])
+		if err != nil {
+			return "", err
+		}
+	}
+	if length < style.minLen || length > style.maxLen {
+		return "", fmt.Errorf("provided length %d must be between %d and %d for style [%s]", length, style.minLen, style.maxLen, param[0])
+	}
+	return randomIdentifier(style.first, style.rest, length)
+}
+
+// generates random string of length with first character picked from first and the rest from rest
+func randomIdentifier(first, rest string, length int) (string, error) {
+	var sb strings.Builder
+	sb.Grow(length)
+	for i := 0; i < length; i++ {
+		alphabet := rest
+		if i == 0 {
+			alphabet = first
+		}
+		idx, err := util.RandInt(len(alphabet))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(alphabet[idx])
+	}
+	return sb.String(), nil
+}
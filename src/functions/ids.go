@@ -0,0 +1,175 @@
+package functions
+
+import (
+	cryptoRand "crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/zeropsio/zParser/v2/src/util"
+)
+
+const (
+	// nanoIDAlphabet is the default URL friendly alphabet of NanoID
+	nanoIDAlphabet      = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	nanoIDDefaultLength = 21
+	// crockfordAlphabet is used by ULID
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// base62Alphabet is used by KSUID
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// ksuidEpoch is KSUID custom epoch (2014-05-13T16:53:20Z) in unix seconds
+	ksuidEpoch = 1400000000
+)
+
+// generates UUID, version is set by the first parameter, one of v4 (random) or v7 (time ordered)
+func (f Functions) generateUUID(param ...string) (string, error) {
+	if err := paramCountCheck(1, len(param)); err != nil {
+		return "", err
+	}
+
+	uuid := make([]byte, 16)
+	if _, err := cryptoRand.Read(uuid); err != nil {
+		return "", err
+	}
+	switch param[0] {
+	case "v4":
+		uuid[6] = (uuid[6] & 0x0f) | 0x40
+	case "v7":
+		ms := uint64(f.now().UnixMilli())
+		uuid[0] = byte(ms >> 40)
+		uuid[1] = byte(ms >> 32)
+		uuid[2] = byte(ms >> 24)
+		uuid[3] = byte(ms >> 16)
+		uuid[4] = byte(ms >> 8)
+		uuid[5] = byte(ms)
+		uuid[6] = (uuid[6] & 0x0f) | 0x70
+	default:
+		return "", fmt.Errorf("unsupported UUID version [%s], supported: [v4, v7]", param[0])
+	}
+	uuid[8] = (uuid[8] & 0x3f) | 0x80 // RFC 9562 variant
+
+	h := hex.EncodeToString(uuid)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32], nil
+}
+
+// generates ULID (48bit millisecond timestamp followed by 80 random bits, Crockford's base32 encoded)
+func (f Functions) generateULID(param ...string) (string, error) {
+	if err := paramCountCheck(0, len(param)); err != nil {
+		return "", err
+	}
+
+	ulid := make([]byte, 16)
+	binary.BigEndian.PutUint64(ulid[:8], uint64(f.now().UnixMilli())<<16)
+	if _, err := cryptoRand.Read(ulid[6:]); err != nil {
+		return "", err
+	}
+
+	// 128 bits are encoded into 26 characters, 5 bits each (first character holds only 3 bits)
+	n := new(big.Int).SetBytes(ulid)
+	out := make([]byte, 26)
+	mask := big.NewInt(0x1f)
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockfordAlphabet[new(big.Int).And(n, mask).Int64()]
+		n.Rsh(n, 5)
+	}
+	return string(out), nil
+}
+
+// generates NanoID of length (first parameter, optional, 21 by default) using alphabet (second parameter, optional)
+func (f Functions) generateNanoID(param ...string) (string, error) {
+	if len(param) > 2 {
+		return "", fmt.Errorf("invalid parameter count, at most 2 expected %d provided", len(param))
+	}
+	length := int64(nanoIDDefaultLength)
+	if len(param) > 0 {
+		var err error
+		length, err = strconv.ParseInt(param[0], 10, 64)
+		if err != nil {
+			return "", err
+		}
+	}
+	if length < 1 || length > maxRandBytesLen {
+		return "", fmt.Errorf("provided length %d must be between 1 and %d", length, maxRandBytesLen)
+	}
+	alphabet := []rune(nanoIDAlphabet)
+	if len(param) == 2 {
+		alphabet = []rune(param[1])
+	}
+	if len(alphabet) < 2 {
+		return "", fmt.Errorf("alphabet must contain at least 2 characters, [%s] provided", string(alphabet))
+	}
+
+	var sb strings.Builder
+	for i := int64(0); i < length; i++ {
+		idx, err := util.RandInt(len(alphabet))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteRune(alphabet[idx])
+	}
+	return sb.String(), nil
+}
+
+// generates KSUID (32bit timestamp followed by 128 random bits, base62 encoded)
+func (f Functions) generateKSUID(param ...string) (string, error) {
+	if err := paramCountCheck(0, len(param)); err != nil {
+		return "", err
+	}
+
+	ksuid := make([]byte, 20)
+	binary.BigEndian.PutUint32(ksuid[:4], uint32(f.now().Unix()-ksuidEpoch))
+	if _, err := cryptoRand.Read(ksuid[4:]); err != nil {
+		return "", err
+	}
+
+	n := new(big.Int).SetBytes(ksuid)
+	base := big.NewInt(int64(len(base62Alphabet)))
+	out := []byte(strings.Repeat(string(base62Alphabet[0]), 27))
+	mod := new(big.Int)
+	for i := len(out) - 1; i >= 0 && n.Sign() > 0; i-- {
+		n.DivMod(n, base, mod)
+		out[i] = base62Alphabet[mod.Int64()]
+	}
+	return string(out), nil
+}
+
+func (f Functions) generateUUIDVar(param ...string) (string, error) {
+	if err := paramCountCheck(2, len(param)); err != nil {
+		return "", err
+	}
+	return f.storeVar(param[0], f.generateUUID, param[1:]...)
+}
+
+func (f Functions) generateULIDVar(param ...string) (string, error) {
+	if err := paramCountCheck(1, len(param)); err != nil {
+		return "", err
+	}
+	return f.storeVar(param[0], f.generateULID)
+}
+
+func (f Functions) generateNanoIDVar(param ...string) (string, error) {
+	if len(param) < 1 {
+		return "", fmt.Errorf("invalid parameter count, at least 1 expected %d provided", len(param))
+	}
+	return f.storeVar(param[0], f.generateNanoID, param[1:]...)
+}
+
+func (f Functions) generateKSUIDVar(param ...string) (string, error) {
+	if err := paramCountCheck(1, len(param)); err != nil {
+		return "", err
+	}
+	return f.storeVar(param[0], f.generateKSUID)
+}
+
+// calls provided function and stores its output under provided name
+func (f Functions) storeVar(name string, fn function, param ...string) (string, error) {
+	out, err := fn(param...)
+	if err != nil {
+		return "", err
+	}
+	f.values[name] = out
+	return out, nil
+}
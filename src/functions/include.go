@@ -0,0 +1,100 @@
+package functions
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SetIncludeRoot sets directory, which files may be included from (using include and import functions).
+// Including files is disabled if the root is empty.
+func (f Functions) SetIncludeRoot(root string) {
+	f.template.includeRoot = root
+}
+
+// parses file (first parameter) relative to the include root and returns its output
+func (f Functions) include(param ...string) (string, error) {
+	if err := paramCountCheck(1, len(param)); err != nil {
+		return "", err
+	}
+	return f.evaluateFile(param[0])
+}
+
+// parses file (first parameter) relative to the include root, only variables and macros defined inside are kept,
+// output is discarded
+func (f Functions) importFile(param ...string) (string, error) {
+	if err := paramCountCheck(1, len(param)); err != nil {
+		return "", err
+	}
+	if _, err := f.evaluateFile(param[0]); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+// reads file confined to the include root and evaluates its content as a template
+func (f Functions) evaluateFile(path string) (string, error) {
+	fullPath, err := f.resolveIncludePath(path)
+	if err != nil {
+		return "", err
+	}
+	for _, included := range f.template.includeStack {
+		if included == fullPath {
+			return "", fmt.Errorf("include cycle detected [%s -> %s]", strings.Join(f.relIncludePaths(), " -> "), path)
+		}
+	}
+
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read included file [%s]: %w", path, err)
+	}
+
+	f.template.includeStack = append(f.template.includeStack, fullPath)
+	defer func() {
+		f.template.includeStack = f.template.includeStack[:len(f.template.includeStack)-1]
+	}()
+
+	// single trailing newline is removed, so the file may be included on its own line
+	return f.evaluateTemplate(strings.TrimSuffix(string(content), "\n"))
+}
+
+// returns absolute path of the included file with symlinks resolved, which must be inside of the include root
+func (f Functions) resolveIncludePath(path string) (string, error) {
+	if f.template.includeRoot == "" {
+		return "", errors.New("including files is disabled, include root is not set")
+	}
+	if path == "" || filepath.IsAbs(path) {
+		return "", fmt.Errorf("invalid include path [%s], path relative to the include root expected", path)
+	}
+	root, err := filepath.Abs(f.template.includeRoot)
+	if err != nil {
+		return "", err
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return "", fmt.Errorf("invalid include root: %w", err)
+	}
+	fullPath, err := filepath.EvalSymlinks(filepath.Join(root, path))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve included file [%s]: %w", path, err)
+	}
+	rel, err := filepath.Rel(root, fullPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("included file [%s] is outside of the include root", path)
+	}
+	return fullPath, nil
+}
+
+// returns paths of currently included files relative to the include root
+func (f Functions) relIncludePaths() []string {
+	root, _ := filepath.EvalSymlinks(f.template.includeRoot)
+	paths := make([]string, len(f.template.includeStack))
+	for i, path := range f.template.includeStack {
+		if rel, err := filepath.Rel(root, path); err == nil {
+			path = rel
+		}
+		paths[i] = path
+	}
+	return paths
+}
@@ -0,0 +1,77 @@
+package functions
+
+import "fmt"
+
+// LazyParam represents parameter of a lazy function, which is evaluated only when its value is requested.
+// It allows functions to skip evaluation (and function calls) of parameters they do not need.
+type LazyParam struct {
+	// Variable contains name of the variable if the parameter is a plain variable reference, empty otherwise
+	Variable string
+	// Raw contains unevaluated source of the parameter
+	Raw string
+
+	eval  func() (string, error)
+	cache *lazyCache
+}
+
+type lazyCache struct {
+	evaluated bool
+	value     string
+	err       error
+}
+
+// NewLazyParam creates LazyParam from its unevaluated source, which is evaluated using provided eval function.
+func NewLazyParam(variable, raw string, eval func() (string, error)) LazyParam {
+	return LazyParam{Variable: variable, Raw: raw, eval: eval, cache: &lazyCache{}}
+}
+
+// Value evaluates the parameter when called for the first time, following calls return the same result.
+func (l LazyParam) Value() (string, error) {
+	if !l.cache.evaluated {
+		l.cache.value, l.cache.err = l.eval()
+		l.cache.evaluated = true
+	}
+	return l.cache.value, l.cache.err
+}
+
+// Evaluate evaluates the parameter again on every call,
+// e.g. when the parameter is used as a template with different variables.
+func (l LazyParam) Evaluate() (string, error) {
+	return l.eval()
+}
+
+// lazyFunction receives parameters unevaluated, so it may evaluate only those it needs (or evaluate them repeatedly)
+type lazyFunction func(param ...LazyParam) (string, error)
+
+// IsLazy returns true if function of provided name receives its parameters unevaluated (see CallLazy).
+// Macros are always lazy.
+func (f Functions) IsLazy(name string) bool {
+	if _, found := f.lazyFunctions[name]; found {
+		return true
+	}
+	_, found := f.macros[name]
+	return found
+}
+
+// CallLazy calls lazy function or a macro, which evaluates only parameters it needs.
+func (f Functions) CallLazy(name string, params ...LazyParam) (string, error) {
+	r, found := f.lazyFunctions[name]
+	if !found {
+		if _, found := f.macros[name]; found {
+			return f.callMacro(name, params...)
+		}
+		return "", fmt.Errorf("lazy function [%s] not found", name)
+	}
+	return r.fn(params...)
+}
+
+// returns already evaluated values as lazy parameters
+func valuesToLazyParams(values []string) []LazyParam {
+	params := make([]LazyParam, len(values))
+	for i, value := range values {
+		params[i] = NewLazyParam("", value, func() (string, error) {
+			return value, nil
+		})
+	}
+	return params
+}
@@ -0,0 +1,124 @@
+package functions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxLoopIterations = 1000
+	defaultIndexVar   = "index"
+	defaultListSep    = ","
+	suffixIndex       = "Index"
+	suffixZeroBased   = "0"
+)
+
+// evaluates template (second parameter) n times (first parameter) and returns concatenated results
+// 1-based index of the iteration is available in variable named by third parameter (optional, index by default)
+// and 0-based index in the same variable suffixed by 0 (e.g. index0)
+func (f Functions) repeat(param ...LazyParam) (string, error) {
+	if len(param) != 2 && len(param) != 3 {
+		return "", fmt.Errorf("invalid parameter count, 2 or 3 expected %d provided", len(param))
+	}
+	count, err := param[0].Value()
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return "", err
+	}
+	if n < 0 || n > maxLoopIterations {
+		return "", fmt.Errorf("provided count %d must be between 0 and %d", n, maxLoopIterations)
+	}
+	indexVar := defaultIndexVar
+	if len(param) == 3 {
+		if indexVar, err = param[2].Value(); err != nil {
+			return "", err
+		}
+	}
+
+	restore := f.saveVars(indexVar, indexVar+suffixZeroBased)
+	defer restore()
+
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		f.values[indexVar] = strconv.Itoa(i + 1)
+		f.values[indexVar+suffixZeroBased] = strconv.Itoa(i)
+		out, err := param[1].Evaluate()
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(out)
+	}
+	return sb.String(), nil
+}
+
+// evaluates template (third parameter) for every item of the list (first parameter) and returns concatenated results
+// list items are separated by separator (fourth parameter, optional, comma by default) and trimmed of spaces
+// current item is available in variable named by second parameter, its 1-based index in the same variable suffixed
+// by Index (e.g. userIndex) and 0-based index in the same variable suffixed by Index0 (e.g. userIndex0)
+func (f Functions) each(param ...LazyParam) (string, error) {
+	if len(param) != 3 && len(param) != 4 {
+		return "", fmt.Errorf("invalid parameter count, 3 or 4 expected %d provided", len(param))
+	}
+	list, err := param[0].Value()
+	if err != nil {
+		return "", err
+	}
+	itemVar, err := param[1].Value()
+	if err != nil {
+		return "", err
+	}
+	separator := defaultListSep
+	if len(param) == 4 {
+		if separator, err = param[3].Value(); err != nil {
+			return "", err
+		}
+	}
+	if strings.TrimSpace(list) == "" {
+		return "", nil
+	}
+	items := strings.Split(list, separator)
+	if len(items) > maxLoopIterations {
+		return "", fmt.Errorf("list contains %d items, max %d allowed", len(items), maxLoopIterations)
+	}
+
+	indexVar := itemVar + suffixIndex
+	restore := f.saveVars(itemVar, indexVar, indexVar+suffixZeroBased)
+	defer restore()
+
+	var sb strings.Builder
+	for i, item := range items {
+		f.values[itemVar] = strings.TrimSpace(item)
+		f.values[indexVar] = strconv.Itoa(i + 1)
+		f.values[indexVar+suffixZeroBased] = strconv.Itoa(i)
+		out, err := param[2].Evaluate()
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(out)
+	}
+	return sb.String(), nil
+}
+
+// saves current values of provided variables and returns function, which restores them
+// variables which did not exist are removed by the returned function
+func (f Functions) saveVars(names ...string) func() {
+	saved := make(map[string]string, len(names))
+	for _, name := range names {
+		if value, found := f.values[name]; found {
+			saved[name] = value
+		}
+	}
+	return func() {
+		for _, name := range names {
+			if value, found := saved[name]; found {
+				f.values[name] = value
+				continue
+			}
+			delete(f.values, name)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package functions
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+const maxMacroDepth = 100
+
+// identifierRegexp matches valid names of macros and their parameters
+var identifierRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// TemplateEvaluator evaluates provided template in the context of the currently running parser.
+type TemplateEvaluator func(template string) (string, error)
+
+// templateState is shared by all copies of Functions, so evaluator set by the parser is visible to bound methods
+type templateState struct {
+	evaluator    TemplateEvaluator
+	macroDepth   int
+	renderDepth  int
+	includeRoot  string
+	includeStack []string // absolute paths of files being included, used to detect cycles
+}
+
+// macro is a user defined function, which evaluates body with parameters bound as variables
+type macro struct {
+	params []string
+	body   string
+}
+
+// UseEvaluator sets evaluator used by functions, which evaluate templates (e.g. macros),
+// returned function restores the previously used evaluator.
+func (f Functions) UseEvaluator(evaluator TemplateEvaluator) (restore func()) {
+	previous := f.template.evaluator
+	f.template.evaluator = evaluator
+	return func() {
+		f.template.evaluator = previous
+	}
+}
+
+// evaluates template using evaluator of the currently running parser
+func (f Functions) evaluateTemplate(template string) (string, error) {
+	if f.template.evaluator == nil {
+		return "", errors.New("templates can be evaluated only during parsing")
+	}
+	return f.template.evaluator(template)
+}
+
+// defines macro named by first parameter, with parameter names passed in all following parameters except the last one,
+// which is the body of the macro; the body is evaluated every time the macro is called
+func (f Functions) define(param ...LazyParam) (string, error) {
+	if len(param) < 2 {
+		return "", fmt.Errorf("invalid parameter count, at least 2 expected %d provided", len(param))
+	}
+	name, err := param[0].Value()
+	if err != nil {
+		return "", err
+	}
+	if !identifierRegexp.MatchString(name) {
+		return "", fmt.Errorf("invalid macro name [%s], only alphanumeric characters and _ are allowed", name)
+	}
+	if _, found := f.functions[name]; found {
+		return "", fmt.Errorf("macro [%s] cannot override built-in function", name)
+	}
+	if _, found := f.lazyFunctions[name]; found {
+		return "", fmt.Errorf("macro [%s] cannot override built-in function", name)
+	}
+
+	m := macro{params: make([]string, 0, len(param)-2)}
+	for _, p := range param[1 : len(param)-1] {
+		paramName, err := p.Value()
+		if err != nil {
+			return "", err
+		}
+		if !identifierRegexp.MatchString(paramName) {
+			return "", fmt.Errorf("invalid parameter name [%s] of macro [%s]", paramName, name)
+		}
+		m.params = append(m.params, paramName)
+	}
+
+	// body stored in a variable is used as a template, otherwise the unevaluated source is used
+	body := param[len(param)-1]
+	m.body = body.Raw
+	if body.Variable != "" {
+		if m.body, err = body.Value(); err != nil {
+			return "", err
+		}
+	}
+
+	f.macros[name] = m
+	return "", nil
+}
+
+// calls macro of provided name, parameters are evaluated and bound as variables, which are available only inside the body
+func (f Functions) callMacro(name string, param ...LazyParam) (string, error) {
+	m, found := f.macros[name]
+	if !found {
+		return "", fmt.Errorf("macro [%s] not found", name)
+	}
+	if len(param) != len(m.params) {
+		return "", fmt.Errorf("invalid parameter count of macro [%s], %d expected %d provided", name, len(m.params), len(param))
+	}
+	if f.template.macroDepth >= maxMacroDepth {
+		return "", fmt.Errorf("max macro nesting depth [%d] exceeded", maxMacroDepth)
+	}
+
+	// evaluate all parameters before binding, so they may use variables of the same name as macro parameters
+	values := make([]string, len(param))
+	for i, p := range param {
+		var err error
+		if values[i], err = p.Value(); err != nil {
+			return "", err
+		}
+	}
+
+	restore := f.saveVars(m.params...)
+	defer restore()
+	for i, paramName := range m.params {
+		f.values[paramName] = values[i]
+	}
+
+	f.template.macroDepth++
+	defer func() {
+		f.template.macroDepth--
+	}()
+	return f.evaluateTemplate(m.body)
+}
@@ -0,0 +1,161 @@
+package functions
+
+import (
+	cryptoRand "crypto/rand"
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"strconv"
+)
+
+// kinds of values which are issued only once during one parse
+const (
+	issuedPort = "port"
+	issuedIP   = "ip"
+	issuedULA  = "ula"
+	issuedMAC  = "mac"
+)
+
+const (
+	minPort       = 1
+	maxPort       = 65535
+	ulaGlobalBits = 40 // length of random Global ID of ULA prefix (RFC 4193)
+	macBits       = 46 // 48 bits without I/G and U/L bits
+)
+
+// returns random port in [min, max] which was not returned yet during this parse
+func (f Functions) randomPort(param ...string) (string, error) {
+	if err := paramCountCheck(2, len(param)); err != nil {
+		return "", err
+	}
+	minVal, err := strconv.Atoi(param[0])
+	if err != nil {
+		return "", err
+	}
+	maxVal, err := strconv.Atoi(param[1])
+	if err != nil {
+		return "", err
+	}
+	if minVal < minPort || maxVal > maxPort {
+		return "", fmt.Errorf("ports must be between %d and %d", minPort, maxPort)
+	}
+	if minVal > maxVal {
+		return "", fmt.Errorf("provided min value %d is greater than max value %d", minVal, maxVal)
+	}
+	size := big.NewInt(int64(maxVal - minVal + 1))
+	return f.pickUnique(issuedPort, size, func(offset *big.Int) string {
+		return strconv.FormatInt(int64(minVal)+offset.Int64(), 10)
+	})
+}
+
+// returns random host address inside of provided CIDR which was not returned yet during this parse
+// network and broadcast addresses of IPv4 networks and Subnet-Router anycast address of IPv6 networks are never returned
+func (f Functions) randomIPInCIDR(param ...string) (string, error) {
+	if err := paramCountCheck(1, len(param)); err != nil {
+		return "", err
+	}
+	prefix, err := netip.ParsePrefix(param[0])
+	if err != nil {
+		return "", err
+	}
+	prefix = prefix.Masked()
+	base := new(big.Int).SetBytes(prefix.Addr().AsSlice())
+	size := new(big.Int).Lsh(big.NewInt(1), uint(prefix.Addr().BitLen()-prefix.Bits()))
+
+	first := big.NewInt(0)
+	switch {
+	case prefix.Addr().Is4() && prefix.Bits() <= 30:
+		// skip network and broadcast addresses
+		first = big.NewInt(1)
+		size.Sub(size, big.NewInt(2))
+	case prefix.Addr().Is6() && prefix.Bits() <= 126:
+		// skip Subnet-Router anycast address
+		first = big.NewInt(1)
+		size.Sub(size, big.NewInt(1))
+	}
+	base.Add(base, first)
+
+	addrLen := prefix.Addr().BitLen() / 8
+	return f.pickUnique(issuedIP, size, func(offset *big.Int) string {
+		ip := new(big.Int).Add(base, offset).FillBytes(make([]byte, addrLen))
+		addr, _ := netip.AddrFromSlice(ip)
+		return addr.String()
+	})
+}
+
+// returns random IPv6 Unique Local Address /48 prefix (RFC 4193) which was not returned yet during this parse
+func (f Functions) randomULAPrefix(param ...string) (string, error) {
+	if err := paramCountCheck(0, len(param)); err != nil {
+		return "", err
+	}
+	size := new(big.Int).Lsh(big.NewInt(1), ulaGlobalBits)
+	return f.pickUnique(issuedULA, size, func(offset *big.Int) string {
+		ip := make([]byte, 16)
+		ip[0] = 0xfd
+		offset.FillBytes(ip[1:6])
+		addr := netip.AddrFrom16([16]byte(ip))
+		return netip.PrefixFrom(addr, 48).String()
+	})
+}
+
+// returns random unicast MAC address which was not returned yet during this parse
+// address is locally administered unless false is passed as the first (optional) parameter
+func (f Functions) randomMAC(param ...string) (string, error) {
+	if len(param) > 1 {
+		return "", fmt.Errorf("invalid parameter count, 0 or 1 expected %d provided", len(param))
+	}
+	local := true
+	if len(param) == 1 {
+		var err error
+		if local, err = strconv.ParseBool(param[0]); err != nil {
+			return "", err
+		}
+	}
+	size := new(big.Int).Lsh(big.NewInt(1), macBits)
+	return f.pickUnique(issuedMAC, size, func(offset *big.Int) string {
+		mac := offset.FillBytes(make([]byte, 6))
+		// offset has only 46 bits, so first octet may be shifted to clear both I/G (multicast) and U/L bits
+		mac[0] <<= 2
+		if local {
+			mac[0] |= 0x02
+		}
+		return net.HardwareAddr(mac).String()
+	})
+}
+
+// picks random offset in [0, size) and returns value created by format, which was not issued yet under kind
+// collisions are resolved by probing following offsets, so free value is always found if there is one
+func (f Functions) pickUnique(kind string, size *big.Int, format func(offset *big.Int) string) (string, error) {
+	if size.Sign() <= 0 {
+		return "", fmt.Errorf("no %s values available", kind)
+	}
+	issued, ok := f.issued[kind]
+	if !ok {
+		issued = map[string]struct{}{}
+		f.issued[kind] = issued
+	}
+
+	offset, err := cryptoRand.Int(cryptoRand.Reader, size)
+	if err != nil {
+		return "", err
+	}
+	// at most len(issued) + 1 distinct offsets must be probed to find the free one
+	probes := big.NewInt(int64(len(issued) + 1))
+	if probes.Cmp(size) > 0 {
+		probes = size
+	}
+	one := big.NewInt(1)
+	for i := int64(0); i < probes.Int64(); i++ {
+		value := format(offset)
+		if _, used := issued[value]; !used {
+			issued[value] = struct{}{}
+			return value, nil
+		}
+		offset.Add(offset, one)
+		if offset.Cmp(size) >= 0 {
+			offset.SetInt64(0)
+		}
+	}
+	return "", fmt.Errorf("all %s values available in requested range were already used", kind)
+}
@@ -0,0 +1,105 @@
+package functions
+
+import (
+	_ "embed"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/zeropsio/zParser/v2/src/util"
+)
+
+const (
+	wordlistLarge      = "large"
+	wordlistShort      = "short"
+	maxPassphraseWords = 64
+	suffixEntropy      = "Entropy"
+)
+
+// EFF wordlists (https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases), licensed under CC BY 3.0 US
+var (
+	//go:embed wordlists/eff_large_wordlist.txt
+	effLargeWordlist string
+	//go:embed wordlists/eff_short_wordlist_2_0.txt
+	effShortWordlist string
+)
+
+// generates passphrase of words (first parameter) randomly picked from wordlist (third parameter, optional, large by default)
+// joined by separator (second parameter, optional, - by default)
+func (f Functions) generatePassphrase(param ...string) (string, error) {
+	passphrase, _, err := generatePassphrase(param...)
+	return passphrase, err
+}
+
+// same as generatePassphrase, but passphrase is stored under name (first parameter)
+// and its entropy in bits under name with Entropy suffix
+func (f Functions) generatePassphraseVar(param ...string) (string, error) {
+	if len(param) < 2 {
+		return "", fmt.Errorf("invalid parameter count, at least 2 expected %d provided", len(param))
+	}
+	passphrase, entropy, err := generatePassphrase(param[1:]...)
+	if err != nil {
+		return "", err
+	}
+	f.values[param[0]] = passphrase
+	f.values[param[0]+suffixEntropy] = strconv.FormatFloat(entropy, 'f', 1, 64)
+	return passphrase, nil
+}
+
+// returns passphrase and its entropy in bits
+func generatePassphrase(param ...string) (string, float64, error) {
+	if len(param) < 1 || len(param) > 3 {
+		return "", 0, fmt.Errorf("invalid parameter count, 1 to 3 expected %d provided", len(param))
+	}
+	count, err := strconv.Atoi(param[0])
+	if err != nil {
+		return "", 0, err
+	}
+	if count < 1 || count > maxPassphraseWords {
+		return "", 0, fmt.Errorf("provided word count %d must be between 1 and %d", count, maxPassphraseWords)
+	}
+	separator := "-"
+	if len(param) >= 2 {
+		separator = param[1]
+	}
+	wordlistName := wordlistLarge
+	if len(param) == 3 {
+		wordlistName = param[2]
+	}
+	wordlist, err := getWordlist(wordlistName)
+	if err != nil {
+		return "", 0, err
+	}
+
+	words := make([]string, count)
+	for i := range words {
+		idx, err := util.RandInt(len(wordlist))
+		if err != nil {
+			return "", 0, err
+		}
+		words[i] = wordlist[idx]
+	}
+	return strings.Join(words, separator), float64(count) * math.Log2(float64(len(wordlist))), nil
+}
+
+// returns words of the wordlist, lines of embedded wordlists are in `dice rolls<TAB>word` format
+func getWordlist(name string) ([]string, error) {
+	var content string
+	switch name {
+	case wordlistLarge:
+		content = effLargeWordlist
+	case wordlistShort:
+		content = effShortWordlist
+	default:
+		return nil, fmt.Errorf("unsupported wordlist [%s], supported: [%s, %s]", name, wordlistLarge, wordlistShort)
+	}
+
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	words := make([]string, len(lines))
+	for i, line := range lines {
+		_, word, _ := strings.Cut(line, "\t")
+		words[i] = word
+	}
+	return words, nil
+}
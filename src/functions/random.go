@@ -0,0 +1,129 @@
+package functions
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/zeropsio/zParser/v2/src/util"
+)
+
+const sampleSeparator = ","
+
+// selects one of the provided values at random with probability given by its weight
+// parameters are pairs of value and its non-negative integer weight, e.g. pickWeighted(a, 3, b, 1)
+// only weights and the selected value are evaluated
+func (f Functions) pickWeighted(param ...LazyParam) (string, error) {
+	if len(param) == 0 || len(param)%2 != 0 {
+		return "", fmt.Errorf("invalid parameter count, even amount of value and weight pairs expected %d provided", len(param))
+	}
+	weights := make([]int, 0, len(param)/2)
+	total := 0
+	for i := 1; i < len(param); i += 2 {
+		value, err := param[i].Value()
+		if err != nil {
+			return "", err
+		}
+		weight, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid weight [%s] of value %d: %w", value, i/2+1, err)
+		}
+		if weight < 0 {
+			return "", fmt.Errorf("weight [%d] of value %d must not be negative", weight, i/2+1)
+		}
+		if weight > math.MaxInt-total {
+			return "", fmt.Errorf("weight [%d] of value %d is too large, sum of all weights must be at most %d", weight, i/2+1, math.MaxInt)
+		}
+		weights = append(weights, weight)
+		total += weight
+	}
+	if total == 0 {
+		return "", errors.New("at least one weight must be greater than zero")
+	}
+
+	n, err := util.RandInt(total)
+	if err != nil {
+		return "", err
+	}
+	for i, weight := range weights {
+		if n < weight {
+			return param[i*2].Value()
+		}
+		n -= weight
+	}
+	return "", errors.New("no value picked") // unreachable, n is always lower than the sum of weights
+}
+
+// returns provided values (all parameters after the first one) in random order joined by separator (first parameter)
+func (f Functions) shuffle(param ...string) (string, error) {
+	if len(param) < 2 {
+		return "", fmt.Errorf("invalid parameter count, at least 2 expected %d provided", len(param))
+	}
+	values := append([]string{}, param[1:]...)
+	if err := shuffleFirst(values, len(values)); err != nil {
+		return "", err
+	}
+	return strings.Join(values, param[0]), nil
+}
+
+// returns n (first parameter) distinct values picked at random from the rest of the parameters joined by a comma
+// only the picked values are evaluated
+func (f Functions) sample(param ...LazyParam) (string, error) {
+	if len(param) < 2 {
+		return "", fmt.Errorf("invalid parameter count, at least 2 expected %d provided", len(param))
+	}
+	size, err := param[0].Value()
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(size)
+	if err != nil {
+		return "", err
+	}
+	values := append([]LazyParam{}, param[1:]...)
+	if n < 1 || n > len(values) {
+		return "", fmt.Errorf("provided sample size %d must be between 1 and amount of values %d", n, len(values))
+	}
+	if err := shuffleFirst(values, n); err != nil {
+		return "", err
+	}
+	picked := make([]string, n)
+	for i, value := range values[:n] {
+		if picked[i], err = value.Value(); err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(picked, sampleSeparator), nil
+}
+
+// selects one of the provided values (all parameters after the first one) based on hash of key (first parameter),
+// so the same key always results in the same value as long as the values do not change
+// only the key and the selected value are evaluated
+func (f Functions) pickByHash(param ...LazyParam) (string, error) {
+	if len(param) < 2 {
+		return "", fmt.Errorf("invalid parameter count, at least 2 expected %d provided", len(param))
+	}
+	key, err := param[0].Value()
+	if err != nil {
+		return "", err
+	}
+	values := param[1:]
+	sum := sha256.Sum256([]byte(key))
+	return values[binary.BigEndian.Uint64(sum[:8])%uint64(len(values))].Value()
+}
+
+// randomly shuffles first n positions of values using Fisher-Yates algorithm with cryptographically secure randomness
+func shuffleFirst[T any](values []T, n int) error {
+	for i := 0; i < n && i < len(values)-1; i++ {
+		j, err := util.RandInt(len(values) - i)
+		if err != nil {
+			return err
+		}
+		values[i], values[i+j] = values[i+j], values[i]
+	}
+	return nil
+}
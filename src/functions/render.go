@@ -0,0 +1,23 @@
+package functions
+
+import (
+	"fmt"
+)
+
+const maxRenderDepth = 100
+
+// parses content (first parameter, usually a variable) as a template using current variables and limits
+func (f Functions) render(param ...string) (string, error) {
+	if err := paramCountCheck(1, len(param)); err != nil {
+		return "", err
+	}
+	if f.template.renderDepth >= maxRenderDepth {
+		return "", fmt.Errorf("max render nesting depth [%d] exceeded", maxRenderDepth)
+	}
+
+	f.template.renderDepth++
+	defer func() {
+		f.template.renderDepth--
+	}()
+	return f.evaluateTemplate(param[0])
+}
@@ -0,0 +1,110 @@
+package functions
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	cryptoRand "crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// signs message (second parameter) using private key (first parameter), signature is encoded using encoding from third parameter
+//   - ED25519 keys produce pure Ed25519 signature
+//   - ECDSA keys produce ASN.1 DER signature of SHA-256/384/512 digest (based on the curve)
+//   - RSA keys produce RSA-PSS signature of SHA-256 digest
+func (f Functions) sign(param ...string) (string, error) {
+	if err := paramCountCheck(3, len(param)); err != nil {
+		return "", err
+	}
+	privateKey, err := parsePrivateKey(param[0])
+	if err != nil {
+		return "", err
+	}
+	message := []byte(param[1])
+
+	var signature []byte
+	switch key := privateKey.(type) {
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, message)
+	case *ecdsa.PrivateKey:
+		hash := ecdsaHash(key.Curve)
+		signature, err = ecdsa.SignASN1(cryptoRand.Reader, key, digest(hash, message))
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPSS(cryptoRand.Reader, key, crypto.SHA256, digest(crypto.SHA256, message), nil)
+	default:
+		return "", fmt.Errorf("unsupported private key type [%T]", privateKey)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to sign: %w", err)
+	}
+	return encodeBytes(signature, param[2])
+}
+
+// verifies signature (third parameter, hex or base64 encoded) of message (second parameter) using public key (first parameter)
+// returns an empty string on success and an error if signature is not valid
+func (f Functions) verifySignature(param ...string) (string, error) {
+	if err := paramCountCheck(3, len(param)); err != nil {
+		return "", err
+	}
+	publicKey, err := parsePublicKey(param[0])
+	if err != nil {
+		return "", err
+	}
+	message := []byte(param[1])
+	signature, err := decodeBytes(strings.TrimSpace(param[2]))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	valid := false
+	switch key := publicKey.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, message, signature)
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(key, digest(ecdsaHash(key.Curve), message), signature)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPSS(key, crypto.SHA256, digest(crypto.SHA256, message), signature, nil) == nil
+	default:
+		return "", fmt.Errorf("unsupported public key type [%T]", publicKey)
+	}
+	if !valid {
+		return "", errors.New("signature verification failed")
+	}
+	return "", nil
+}
+
+// parses PEM encoded private key (PKCS#8, PKCS#1, SEC 1 or OpenSSH format)
+func parsePrivateKey(in string) (any, error) {
+	privateKey, err := ssh.ParseRawPrivateKey([]byte(strings.TrimSpace(in)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	// OpenSSH format returns pointer to ed25519 key, unlike PKCS#8
+	if key, ok := privateKey.(*ed25519.PrivateKey); ok {
+		return *key, nil
+	}
+	return privateKey, nil
+}
+
+// returns hash matching the size of the curve
+func ecdsaHash(curve elliptic.Curve) crypto.Hash {
+	switch curve.Params().BitSize {
+	case 384:
+		return crypto.SHA384
+	case 521:
+		return crypto.SHA512
+	}
+	return crypto.SHA256
+}
+
+func digest(hash crypto.Hash, message []byte) []byte {
+	h := hash.New()
+	h.Write(message)
+	return h.Sum(nil)
+}
@@ -0,0 +1,197 @@
+package functions
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+type paramType int
+
+const (
+	typeString paramType = iota
+	typeInt
+	typeBool
+)
+
+// paramSpec describes one parameter of a function, used to bind named parameters to their positions
+type paramSpec struct {
+	name     string
+	t        paramType
+	optional bool
+	def      string // value used for optional parameter, which is omitted, but followed by a provided parameter
+}
+
+// registered is a function together with its parameters, which are nil for functions with variable parameter count
+type registered[T function | lazyFunction] struct {
+	fn     T
+	params []paramSpec
+}
+
+// returns parameters of a function with fixed parameter count (not nil even if the function takes no parameters)
+func takes(params ...paramSpec) []paramSpec {
+	if params == nil {
+		return []paramSpec{}
+	}
+	return params
+}
+
+func text(name string) paramSpec {
+	return required(name, typeString)
+}
+
+func required(name string, t paramType) paramSpec {
+	return paramSpec{name: name, t: t}
+}
+
+func optional(name string, t paramType, def string) paramSpec {
+	return paramSpec{name: name, t: t, optional: true, def: def}
+}
+
+// returns error if value cannot be used as a value of the parameter
+func (s paramSpec) check(value string) error {
+	switch s.t {
+	case typeInt:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("invalid value [%s] of parameter [%s], integer expected", value, s.name)
+		}
+	case typeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid value [%s] of parameter [%s], boolean expected", value, s.name)
+		}
+	case typeString:
+	}
+	return nil
+}
+
+// returns parameters of function with provided name, false is returned for functions with variable parameter count,
+// which cannot be called with named parameters
+func (f Functions) signature(name string) ([]paramSpec, bool) {
+	if r, found := f.functions[name]; found {
+		return r.params, r.params != nil
+	}
+	if r, found := f.lazyFunctions[name]; found {
+		return r.params, r.params != nil
+	}
+	if m, found := f.macros[name]; found {
+		spec := make([]paramSpec, len(m.params))
+		for i, name := range m.params {
+			spec[i] = text(name)
+		}
+		return spec, true
+	}
+	return nil, false
+}
+
+// ParameterNames returns names of parameters of function with provided name,
+// nil is returned for functions, which cannot be called with named parameters.
+func (f Functions) ParameterNames(name string) []string {
+	spec, found := f.signature(name)
+	if !found {
+		return nil
+	}
+	names := make([]string, len(spec))
+	for i, s := range spec {
+		names[i] = s.name
+	}
+	return names
+}
+
+// BindParameters returns parameters of function with provided name ordered by its signature.
+// Parameters with a name (names[i] is not empty) are moved to the position of the parameter of that name
+// and their values are type checked, parameters without a name are kept in their positions.
+// If no parameter has a name, parameters are returned unchanged.
+func (f Functions) BindParameters(name string, params []string, names []string) ([]string, error) {
+	if !hasNames(names) {
+		return params, nil
+	}
+	spec, found := f.signature(name)
+	if !found {
+		return nil, fmt.Errorf("function [%s] does not support named parameters", name)
+	}
+	bound, err := bindParameters(name, spec, params, names, func(value string) string {
+		return value
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range bound {
+		if err := spec[i].check(value); err != nil {
+			return nil, err
+		}
+	}
+	return bound, nil
+}
+
+// BindLazyParameters returns parameters of lazy function (or macro) with provided name ordered by its signature,
+// see BindParameters. Parameters are not evaluated, so their types are checked by the function itself.
+func (f Functions) BindLazyParameters(name string, params []LazyParam, names []string) ([]LazyParam, error) {
+	if !hasNames(names) {
+		return params, nil
+	}
+	spec, found := f.signature(name)
+	if !found {
+		return nil, fmt.Errorf("function [%s] does not support named parameters", name)
+	}
+	return bindParameters(name, spec, params, names, func(value string) LazyParam {
+		return NewLazyParam("", value, func() (string, error) {
+			return value, nil
+		})
+	})
+}
+
+func hasNames(names []string) bool {
+	return slices.ContainsFunc(names, func(name string) bool {
+		return name != ""
+	})
+}
+
+// orders parameters by spec, optional parameters, which are omitted, but followed by a provided parameter,
+// are set to their default value, trailing omitted parameters are left out, so the function uses its own defaults
+func bindParameters[T any](function string, spec []paramSpec, params []T, names []string, value func(string) T) ([]T, error) {
+	bound := make([]T, len(spec))
+	provided := make([]bool, len(spec))
+	named := false
+	for i, param := range params {
+		idx := i
+		if names[i] == "" {
+			if named {
+				return nil, errors.New("positional parameter must not follow named parameters")
+			}
+			if idx >= len(spec) {
+				return nil, fmt.Errorf("invalid parameter count, at most %d expected %d provided", len(spec), len(params))
+			}
+		} else {
+			named = true
+			idx = slices.IndexFunc(spec, func(s paramSpec) bool {
+				return s.name == names[i]
+			})
+			if idx == -1 {
+				return nil, fmt.Errorf("unknown parameter [%s] of function [%s]", names[i], function)
+			}
+			if provided[idx] {
+				return nil, fmt.Errorf("parameter [%s] of function [%s] provided more than once", names[i], function)
+			}
+		}
+		bound[idx] = param
+		provided[idx] = true
+	}
+
+	last := -1
+	for i, s := range spec {
+		if provided[i] {
+			last = i
+			continue
+		}
+		if !s.optional {
+			return nil, fmt.Errorf("missing parameter [%s] of function [%s]", s.name, function)
+		}
+	}
+	for i := 0; i < last; i++ {
+		if !provided[i] {
+			bound[i] = value(spec[i].def)
+		}
+	}
+	return bound[:last+1], nil
+}
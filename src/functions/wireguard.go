@@ -0,0 +1,96 @@
+package functions
+
+import (
+	cryptoRand "crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+// generates WireGuard (Curve25519) key pair and a preshared key, all base64 encoded, and stores them under name (first parameter)
+// public key is returned
+func (f Functions) generateWireGuardKey(param ...string) (string, error) {
+	if err := paramCountCheck(1, len(param)); err != nil {
+		return "", err
+	}
+
+	privateKey := make([]byte, curve25519.ScalarSize)
+	if _, err := cryptoRand.Read(privateKey); err != nil {
+		return "", err
+	}
+	// clamp private key the same way `wg genkey` does
+	privateKey[0] &= 248
+	privateKey[31] = (privateKey[31] & 127) | 64
+
+	publicKey, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		return "", err
+	}
+
+	presharedKey := make([]byte, 32)
+	if _, err := cryptoRand.Read(presharedKey); err != nil {
+		return "", err
+	}
+
+	name := param[0]
+	f.values[name+suffixPublic] = base64.StdEncoding.EncodeToString(publicKey)
+	f.values[name+suffixPrivate] = base64.StdEncoding.EncodeToString(privateKey)
+	f.values[name+suffixPresharedKey] = base64.StdEncoding.EncodeToString(presharedKey)
+
+	return f.values[name+suffixPublic], nil
+}
+
+// renders WireGuard [Interface] section using private key stored under name (first parameter) by generateWireGuardKey
+// second parameter is interface address, optional third parameter is listen port
+func (f Functions) wireGuardInterface(param ...string) (string, error) {
+	if len(param) != 2 && len(param) != 3 {
+		return "", fmt.Errorf("invalid parameter count, 2 or 3 expected %d provided", len(param))
+	}
+	privateKey, found := f.values[param[0]+suffixPrivate]
+	if !found {
+		return "", fmt.Errorf("WireGuard key [%s] not found, generate it using generateWireGuardKey first", param[0])
+	}
+
+	lines := []string{
+		"[Interface]",
+		"PrivateKey = " + privateKey,
+		"Address = " + param[1],
+	}
+	if len(param) == 3 && param[2] != "" {
+		port, err := strconv.ParseUint(param[2], 10, 16)
+		if err != nil {
+			return "", fmt.Errorf("invalid listen port [%s]: %w", param[2], err)
+		}
+		lines = append(lines, "ListenPort = "+strconv.FormatUint(port, 10))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// renders WireGuard [Peer] section using public key stored under name (first parameter) by generateWireGuardKey
+// second parameter is allowed IPs, optional third parameter is endpoint and optional fourth parameter is preshared key,
+// which MUST be the same on both sides of the connection (e.g. PresharedKey of one of the peers)
+func (f Functions) wireGuardPeer(param ...string) (string, error) {
+	if len(param) < 2 || len(param) > 4 {
+		return "", fmt.Errorf("invalid parameter count, 2 to 4 expected %d provided", len(param))
+	}
+	publicKey, found := f.values[param[0]+suffixPublic]
+	if !found {
+		return "", fmt.Errorf("WireGuard key [%s] not found, generate it using generateWireGuardKey first", param[0])
+	}
+
+	lines := []string{
+		"[Peer]",
+		"PublicKey = " + publicKey,
+	}
+	if len(param) == 4 && param[3] != "" {
+		lines = append(lines, "PresharedKey = "+param[3])
+	}
+	lines = append(lines, "AllowedIPs = "+param[1])
+	if len(param) >= 3 && param[2] != "" {
+		lines = append(lines, "Endpoint = "+param[2])
+	}
+	return strings.Join(lines, "\n"), nil
+}
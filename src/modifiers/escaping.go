@@ -0,0 +1,109 @@
+package modifiers
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// escapes string to be used inside YAML double-quoted scalar ("..."), surrounding quotes are NOT added
+func yamlDoubleQuoted(in string) (string, error) {
+	var sb strings.Builder
+	sb.Grow(len(in))
+	for _, r := range in {
+		switch r {
+		case '\\':
+			sb.WriteString(`\\`)
+		case '"':
+			sb.WriteString(`\"`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\u0085':
+			sb.WriteString(`\N`)
+		case '\u2028':
+			sb.WriteString(`\L`)
+		case '\u2029':
+			sb.WriteString(`\P`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				sb.WriteString(fmt.Sprintf(`\x%02x`, r))
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String(), nil
+}
+
+// escapes string to be used inside YAML single-quoted scalar ('...'), surrounding quotes are NOT added
+// newlines are doubled, because single newline is folded into a space inside single-quoted scalars
+func yamlSingleQuoted(in string) (string, error) {
+	for _, r := range in {
+		if r != '\n' && (r < 0x20 && r != '\t' || r == 0x7f) {
+			return "", fmt.Errorf("control character [%U] cannot be represented in YAML single-quoted scalar", r)
+		}
+	}
+	out := strings.ReplaceAll(in, "'", "''")
+	return strings.ReplaceAll(out, "\n", "\n\n"), nil
+}
+
+// escapes string to be used inside JSON string ("..."), surrounding quotes are NOT added
+func jsonString(in string) (string, error) {
+	buf := bytes.Buffer{}
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(in); err != nil {
+		return "", err
+	}
+	// strip surrounding quotes and newline added by the encoder
+	out := strings.TrimSuffix(buf.String(), "\n")
+	return out[1 : len(out)-1], nil
+}
+
+// wraps string into single quotes, so it is interpreted literally by POSIX shells
+func shellQuote(in string) (string, error) {
+	if strings.ContainsRune(in, 0) {
+		return "", errors.New("NULL byte cannot be used in shell arguments")
+	}
+	return "'" + strings.ReplaceAll(in, "'", `'\''`) + "'", nil
+}
+
+// escapes XML special characters, usable both in text content and attribute values
+func xmlEscape(in string) (string, error) {
+	buf := bytes.Buffer{}
+	if err := xml.EscapeText(&buf, []byte(in)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// wraps string into double quotes used by .env files, escaping \, ", $ and newlines
+func envQuote(in string) (string, error) {
+	if strings.ContainsRune(in, 0) {
+		return "", errors.New("NULL byte cannot be used in env values")
+	}
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`$`, `\$`,
+		"\n", `\n`,
+		"\r", `\r`,
+	)
+	return `"` + replacer.Replace(in) + `"`, nil
+}
+
+// wraps string into single quotes as standard SQL string literal, doubling all single quotes
+// backslashes are kept as they are (standard conforming strings)
+func sqlLiteral(in string) (string, error) {
+	if strings.ContainsRune(in, 0) {
+		return "", errors.New("NULL byte cannot be used in SQL string literals")
+	}
+	return "'" + strings.ReplaceAll(in, "'", "''") + "'", nil
+}
@@ -1,10 +1,11 @@
 package modifiers
 
 import (
-	"crypto/sha256"
-	"crypto/sha512"
 	"encoding/hex"
 	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/fnv"
 	"strings"
 
 	"golang.org/x/crypto/bcrypt"
@@ -22,18 +23,14 @@ type Modifiers struct {
 
 func NewModifiers() *Modifiers {
 	titleCaser := cases.Title(language.English, cases.NoLower)
-	return &Modifiers{
+	m := &Modifiers{
 		modifiers: map[string]modifyFunc{
-			"sha256": func(in string) (string, error) {
-				hash := sha256.New()
-				hash.Write([]byte(in))
-				return hex.EncodeToString(hash.Sum(nil)), nil
-			},
-			"sha512": func(in string) (string, error) {
-				hash := sha512.New()
-				hash.Write([]byte(in))
-				return hex.EncodeToString(hash.Sum(nil)), nil
-			},
+			"crc32": hexHash(func() hash.Hash {
+				return crc32.NewIEEE()
+			}),
+			"fnv": hexHash(func() hash.Hash {
+				return fnv.New64a()
+			}),
 			"bcrypt": func(in string) (string, error) {
 				hash, err := bcrypt.GenerateFromPassword([]byte(in), 11) // cost set to not overload the parser service
 				return string(hash), err
@@ -41,6 +38,12 @@ func NewModifiers() *Modifiers {
 			"argon2id": func(in string) (string, error) {
 				return util.Argon2IDPasswordHash(in, util.DefaultArgon2idConf())
 			},
+			"scrypt": func(in string) (string, error) {
+				return util.ScryptPasswordHash(in, util.DefaultScryptConf())
+			},
+			"pbkdf2-sha256": func(in string) (string, error) {
+				return util.PBKDF2SHA256PasswordHash(in, util.DefaultPBKDF2Conf())
+			},
 			"toHex": func(in string) (string, error) {
 				return hex.EncodeToString([]byte(in)), nil
 			},
@@ -59,8 +62,21 @@ func NewModifiers() *Modifiers {
 			"noop": func(in string) (string, error) {
 				return in, nil
 			},
+			"yamlDoubleQuoted": yamlDoubleQuoted,
+			"yamlSingleQuoted": yamlSingleQuoted,
+			"jsonString":       jsonString,
+			"shellQuote":       shellQuote,
+			"xmlEscape":        xmlEscape,
+			"envQuote":         envQuote,
+			"sqlLiteral":       sqlLiteral,
 		},
 	}
+	// cryptographic hashes share their implementation with the hmac function
+	for _, name := range util.HashNames() {
+		newHash, _ := util.HashFunc(name) // error is returned only for unsupported names
+		m.modifiers[name] = hexHash(newHash)
+	}
+	return m
 }
 
 func (f Modifiers) Call(name, value string) (string, error) {
@@ -86,3 +102,12 @@ func (f Modifiers) CallBatch(value string, modifiers ...string) (string, error)
 	}
 	return value, nil
 }
+
+// returns modifyFunc which hashes input using provided hash and returns the sum encoded into hex
+func hexHash(newHash func() hash.Hash) modifyFunc {
+	return func(in string) (string, error) {
+		h := newHash()
+		h.Write([]byte(in))
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+}
@@ -1,5 +1,7 @@
 package parser
 
+import "time"
+
 type OptionFunc func(p *Parser)
 
 type MultiLineOutputHandling int
@@ -24,3 +26,27 @@ func WithMaxFunctionCount(c int) OptionFunc {
 		p.maxFunctionCount = c
 	}
 }
+
+// WithVariables stores provided variables, so they can be used in parsed file as any other variable
+func WithVariables(variables map[string]string) OptionFunc {
+	return func(p *Parser) {
+		for name, value := range variables {
+			p.valueStore[name] = value
+		}
+	}
+}
+
+// WithClock sets function returning current time used by time based functions (e.g. getDatetime, totpCode)
+func WithClock(now func() time.Time) OptionFunc {
+	return func(p *Parser) {
+		p.now = now
+	}
+}
+
+// WithIncludeRoot sets directory, which files may be included from using include and import functions
+// Files outside of the directory cannot be included, including files is disabled if the root is not set
+func WithIncludeRoot(root string) OptionFunc {
+	return func(p *Parser) {
+		p.includeRoot = root
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/zeropsio/zParser/v2/src/functions"
 	"github.com/zeropsio/zParser/v2/src/metaError"
@@ -20,10 +21,16 @@ const (
 	itemStartChar  = '<'
 	itemEndChar    = '>'
 	funcStartChar  = '@' // combined with itemStartChar which must be preceding funcStartChar
+	varStartChar   = '$' // combined with itemStartChar which must be preceding varStartChar
 	modifierChar   = '|'
 	paramStartChar = '('
 	paramEndChar   = ')'
 	paramSepChar   = ','
+	commentChar    = '#' // combined with itemStartChar, content until commentChar followed by itemEndChar is dropped
+	verbatimChar   = '%' // combined with itemStartChar, content until verbatimChar followed by itemEndChar is not parsed
+	silentChar     = '!' // following funcStartChar, output of the function is discarded
+	trimChar       = '-' // following itemStartChar or preceding itemEndChar, adjacent whitespaces are removed
+	whitespaceSet  = " \t\r\n"
 )
 
 type Parser struct {
@@ -34,12 +41,25 @@ type Parser struct {
 	maxFunctionCount int
 	// Sets how multiline output of functions will be formatted, defaults to no modifications
 	multiLineOutputHandling MultiLineOutputHandling
+	// Returns current time used by time based functions, defaults to time.Now
+	now func() time.Time
+	// Directory which files may be included from, including is disabled by default
+	includeRoot string
+
+	ctx context.Context
 
 	functionCount int
 	currentLine   int
 	currentChar   int
 	currentItem   *parserItem
 
+	block     rune // commentChar or verbatimChar if comment or verbatim block is being parsed, 0 otherwise
+	blockHeld bool // whether the last rune was the block char, which is written only if it does not end the block
+	blockTrim bool // whether whitespaces following currently parsed block should be removed
+
+	trimSpace    bool   // whether whitespaces are being removed after an item ending with trimChar
+	pendingSpace string // trailing whitespaces of the output, written only when followed by other content
+
 	indentChar  rune
 	indentCount int
 
@@ -57,8 +77,8 @@ func NewParser(in io.Reader, out io.Writer, options ...OptionFunc) *Parser {
 
 		maxFunctionCount:        -1,
 		multiLineOutputHandling: MultilinePreserved,
+		now:                     time.Now,
 
-		functions: functions.NewFunctions(values),
 		mutations: modifiers.NewModifiers(),
 
 		currentLine: 1,
@@ -67,11 +87,19 @@ func NewParser(in io.Reader, out io.Writer, options ...OptionFunc) *Parser {
 	for _, option := range options {
 		option(p)
 	}
+	p.functions = functions.NewFunctions(values)
+	p.functions.SetClock(p.now)
+	p.functions.SetIncludeRoot(p.includeRoot)
 	return p
 }
 
 func (p *Parser) Parse(ctx context.Context) error {
 	var previousRune rune
+	p.ctx = ctx
+
+	// functions evaluating templates (e.g. macros) must use the parser which is currently running
+	restoreEvaluator := p.functions.UseEvaluator(p.evaluate)
+	defer restoreEvaluator()
 
 	skipInitialize := 0      // if above 0 skips X characters, decrementing variable with every skip
 	indentSection := true    // whether any char other than TAB or SPACE occurred on current line (set to false with first such occurrence)
@@ -94,6 +122,20 @@ func (p *Parser) Parse(ctx context.Context) error {
 			}()
 
 			p.currentChar++
+
+			// remove whitespaces following an item ending with trimChar
+			// indentation is not reset on newline, as the output continues on the line of the item
+			if p.trimSpace {
+				if strings.ContainsRune(whitespaceSet, r) {
+					if r == newlineChar {
+						p.currentLine++
+						p.currentChar = 0
+					}
+					return nil
+				}
+				p.trimSpace = false
+			}
+
 			if indentSection {
 				indentSection = p.countIndent(r)
 			}
@@ -109,6 +151,35 @@ func (p *Parser) Parse(ctx context.Context) error {
 				indentSection = true
 			}
 
+			// parameters of lazy functions are captured as they are and evaluated later
+			if p.currentItem.IsCapturingRawParameters() {
+				if err := p.currentItem.CaptureRawParameterRune(r); err != nil {
+					return p.fmtErr(previousRune, r, err)
+				}
+				return nil
+			}
+
+			// content of comment is dropped and content of verbatim block is written as it is
+			if p.block != 0 {
+				return p.processBlockRune(r)
+			}
+
+			// trimChar following itemStartChar of a function, comment or verbatim block removes preceding whitespaces
+			if previousRune == itemStartChar && r == trimChar && skipInitialize == 0 &&
+				(p.peekIs(string(funcStartChar)) || p.peekIs(string(commentChar)) || p.peekIs(string(verbatimChar))) {
+				p.trimPrecedingSpace()
+				p.currentChar++
+				if r, _, err = p.in.ReadRune(); err != nil {
+					return err
+				}
+			}
+
+			// beginning of comment <# ... #> or verbatim block <% ... %>
+			if previousRune == itemStartChar && (r == commentChar || r == verbatimChar) && skipInitialize == 0 {
+				p.block = r
+				return nil
+			}
+
 			// beginning of string or function
 			// - string like << abcd | upper >> has only inside processed and surrounding < and > are preserved, resulting in < ABCD >
 			// - strings like <> are be skipped
@@ -157,10 +228,7 @@ func (p *Parser) Parse(ctx context.Context) error {
 
 			// no item is being processed, just write to output
 			if p.currentItem == nil {
-				if _, err := p.out.WriteRune(r); err != nil {
-					return err
-				}
-				return nil
+				return p.writeOutput(string(r))
 			}
 
 			// end of currently processed item
@@ -171,17 +239,27 @@ func (p *Parser) Parse(ctx context.Context) error {
 				return nil
 			}
 
+			// trimChar preceding itemEndChar of a function removes following whitespaces
+			if r == trimChar && p.currentItem.IsFunction() && p.currentItem.currSection == itemSectionModifiers && p.peekIs(string(itemEndChar)) {
+				p.currentItem.trimSpace = true
+				return nil
+			}
+
 			// if we are inside a function, detect section of the function declaration we are parsing
 			cont, err := p.currentItem.ProcessCurrentFunctionSection(r)
 			if err != nil {
 				return p.fmtErr(previousRune, r, err)
 			}
 			if cont {
+				if r == paramStartChar {
+					p.currentItem.lazy = p.functions.IsLazy(p.currentItem.name)
+					p.currentItem.paramNames = p.functions.ParameterNames(p.currentItem.name)
+				}
 				return nil
 			}
 
-			// switch to modifier section for strings
-			if r == modifierChar && p.currentItem.IsString() {
+			// switch to modifier section for strings and variables
+			if r == modifierChar && (p.currentItem.IsString() || p.currentItem.IsVariable()) {
 				p.currentItem.currSection = itemSectionModifiers
 				p.currentItem.currModifier++
 				return nil // eat |
@@ -197,6 +275,12 @@ func (p *Parser) Parse(ctx context.Context) error {
 		}
 	}
 
+	if p.block != 0 {
+		return p.fmtErr(previousRune, p.block, fmt.Errorf("missing closing %c%c", p.block, itemEndChar))
+	}
+	if _, err := p.out.WriteString(p.pendingSpace); err != nil {
+		return err
+	}
 	return p.out.Flush()
 }
 
@@ -251,13 +335,10 @@ func (p *Parser) countIndent(r rune) bool {
 // - name of the current item if it's a string
 func (p *Parser) writeRune(r rune) error {
 	if p.currentItem == nil {
-		if _, err := p.out.WriteRune(r); err != nil {
-			return err
-		}
-		return nil
+		return p.writeOutput(string(r))
 	}
 	if p.currentItem.IsFunction() {
-		p.currentItem.parameters[p.currentItem.currParam].value += string(r)
+		p.currentItem.currentParameter().value += string(r)
 	} else {
 		p.currentItem.name += string(r)
 	}
@@ -265,6 +346,75 @@ func (p *Parser) writeRune(r rune) error {
 	return nil
 }
 
+// processes rune inside of comment or verbatim block
+// block char is held back until the next rune, so it is not written if it is followed by itemEndChar ending the block
+func (p *Parser) processBlockRune(r rune) error {
+	if p.blockHeld && r == itemEndChar {
+		p.block = 0
+		p.blockHeld = false
+		p.trimSpace = p.blockTrim
+		p.blockTrim = false
+		return nil
+	}
+	// trimChar preceding the end of the block removes following whitespaces
+	if r == trimChar && p.peekIs(string([]rune{p.block, itemEndChar})) {
+		p.blockTrim = true
+		return nil
+	}
+	if p.block == commentChar {
+		p.blockHeld = r == commentChar
+		return nil
+	}
+
+	if p.blockHeld {
+		if err := p.writeVerbatimRune(p.block); err != nil {
+			return err
+		}
+	}
+	p.blockHeld = r == verbatimChar
+	if p.blockHeld {
+		return nil
+	}
+	return p.writeVerbatimRune(r)
+}
+
+// writes provided rune to output or to currentItem without any processing
+func (p *Parser) writeVerbatimRune(r rune) error {
+	if p.currentItem == nil {
+		return p.writeOutput(string(r))
+	}
+	p.currentItem.AddVerbatimRune(r)
+	return nil
+}
+
+// writes content to the output, trailing whitespaces are held back until other content is written,
+// so they may be removed by an item starting with trimChar
+func (p *Parser) writeOutput(content string) error {
+	content = p.pendingSpace + content
+	trimmed := strings.TrimRight(content, whitespaceSet)
+	p.pendingSpace = content[len(trimmed):]
+	if _, err := p.out.WriteString(trimmed); err != nil {
+		return err
+	}
+	return nil
+}
+
+// removes whitespaces preceding an item starting with trimChar,
+// from the output if no item is being processed or from the content of the current item
+func (p *Parser) trimPrecedingSpace() {
+	if p.currentItem == nil {
+		p.pendingSpace = ""
+		return
+	}
+	p.currentItem.TrimTrailingSpace()
+}
+
+// returns true if the input continues with provided string, nothing is read from the input
+func (p *Parser) peekIs(s string) bool {
+	next, err := p.in.Peek(len(s))
+	return err == nil && string(next) == s
+}
+
 // initializes a new item
 // if currentItem already exists, it's set as a parent of new item
 func (p *Parser) initializeItem(r rune) {
@@ -291,6 +441,8 @@ func (p *Parser) processCurrentItem() error {
 		return nil
 	}
 
+	p.currentItem.ResolveVariableShorthand()
+
 	out := ""
 	switch p.currentItem.t {
 	case itemTypeFunction:
@@ -298,10 +450,25 @@ func (p *Parser) processCurrentItem() error {
 			return err
 		}
 
-		params, err := p.currentItem.GetInterpretedParameters(p.valueStore)
+		if p.currentItem.lazy {
+			params, names := p.lazyParameters()
+			params, err := p.functions.BindLazyParameters(p.currentItem.name, params, names)
+			if err != nil {
+				return err
+			}
+			if out, err = p.functions.CallLazy(p.currentItem.name, params...); err != nil {
+				return err
+			}
+			break
+		}
+
+		params, names, err := p.currentItem.GetInterpretedParameters(p.valueStore)
 		if err != nil {
 			return err
 		}
+		if params, err = p.functions.BindParameters(p.currentItem.name, params, names); err != nil {
+			return err
+		}
 
 		out, err = p.functions.Call(p.currentItem.name, params...)
 		if err != nil {
@@ -309,6 +476,16 @@ func (p *Parser) processCurrentItem() error {
 		}
 	case itemTypeString:
 		out = p.currentItem.name
+	case itemTypeVariable:
+		if err := p.incrementFunctionCount(); err != nil {
+			return err
+		}
+		name := strings.TrimSpace(p.currentItem.name)
+		value, found := p.valueStore[name]
+		if !found {
+			return fmt.Errorf("variable [%s] not found", name)
+		}
+		out = value
 	default:
 		return fmt.Errorf("unsupported item type [%d]", p.currentItem.t) // this should never happen
 	}
@@ -324,30 +501,89 @@ func (p *Parser) processCurrentItem() error {
 		}
 	}
 
-	// handle newlines for function output (do not touch user entered text)
-	if p.currentItem.t == itemTypeFunction {
+	// output of silent functions is discarded, they are called only for their side effects (e.g. setting variables)
+	if p.currentItem.silent {
+		out = ""
+	}
+	p.trimSpace = p.currentItem.trimSpace
+
+	// handle newlines for function and variable output (do not touch user entered text)
+	// output of lazy functions is comprised of parameters already handled by the nested parser
+	if (p.currentItem.IsFunction() && !p.currentItem.lazy) || p.currentItem.IsVariable() {
 		out = p.handleMultiline(out)
 	}
 
 	// if parent exists, write output to the parameters and move back to processing of the parent
 	if p.currentItem.parent != nil {
 		p.currentItem = p.currentItem.parent
-		if p.currentItem.IsFunction() {
-			p.currentItem.parameters[p.currentItem.currParam].value += out
-			p.currentItem.parameters[p.currentItem.currParam].isVariable = false
-		} else {
-			p.currentItem.name += out
-		}
-
+		p.currentItem.AddItemOutput(out)
 		return nil
 	}
 
 	p.currentItem = nil
 
-	if _, err := p.out.WriteString(out); err != nil {
-		return err
+	return p.writeOutput(out)
+}
+
+// returns parameters of currentItem, which are evaluated only when their value is requested by the lazy function,
+// and their names (empty for positional parameters)
+func (p *Parser) lazyParameters() ([]functions.LazyParam, []string) {
+	raw, variables, names := p.currentItem.GetRawParameters()
+	params := make([]functions.LazyParam, len(raw))
+	for idx, value := range raw {
+		if variables[idx] {
+			params[idx] = functions.NewLazyParam(value, value, func() (string, error) {
+				val, found := p.valueStore[value]
+				if !found {
+					return "", fmt.Errorf("variable [%s] not found", value)
+				}
+				return val, nil
+			})
+			continue
+		}
+		params[idx] = functions.NewLazyParam("", value, func() (string, error) {
+			return p.evaluate(value)
+		})
 	}
-	return nil
+	return params, names
+}
+
+// evaluates provided template using a nested parser, which shares values, functions and the function call counter
+// indentation of lazy currentItem is used for the first line of the template, so multiline output is indented correctly,
+// output of other functions is indented as a whole after they return
+func (p *Parser) evaluate(template string) (string, error) {
+	var indentChar rune
+	var indentCount int
+	if p.currentItem != nil && p.currentItem.lazy {
+		indentChar, indentCount = p.currentItem.indentChar, p.currentItem.indentCount
+	}
+
+	out := strings.Builder{}
+	nested := &Parser{
+		in:  bufio.NewReader(strings.NewReader(template)),
+		out: bufio.NewWriter(&out),
+
+		maxFunctionCount:        p.maxFunctionCount,
+		multiLineOutputHandling: p.multiLineOutputHandling,
+		now:                     p.now,
+
+		functionCount: p.functionCount,
+		currentLine:   1,
+
+		indentChar:  indentChar,
+		indentCount: indentCount,
+
+		functions: p.functions,
+		mutations: p.mutations,
+
+		valueStore: p.valueStore,
+	}
+	err := nested.Parse(p.ctx)
+	p.functionCount = nested.functionCount
+	if err != nil {
+		return "", err
+	}
+	return out.String(), nil
 }
 
 func (p *Parser) handleMultiline(in string) string {
@@ -3,6 +3,8 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -12,6 +14,7 @@ type itemSection int
 const (
 	itemTypeFunction itemType = iota
 	itemTypeString
+	itemTypeVariable // shorthand for getVar function, e.g. <$name>
 )
 const (
 	itemSectionName itemSection = iota // function name for name itemTypeFunction or string content for itemTypeString
@@ -19,17 +22,23 @@ const (
 	itemSectionModifiers
 )
 
+// variableNameRegexp matches names of variables, which may be used in the variable shorthand (e.g. <$name>)
+var variableNameRegexp = regexp.MustCompile(`^\s*[a-zA-Z_][a-zA-Z0-9_.-]*\s*$`)
+
 func (t itemType) String() string {
 	switch t {
 	case itemTypeFunction:
 		return "function"
 	case itemTypeString:
 		return "string"
+	case itemTypeVariable:
+		return "variable"
 	}
 	return "unknown"
 }
 
 type itemParam struct {
+	name       string // name of the parameter passed as name=<value>, empty for positional parameters
 	value      string
 	isVariable bool
 }
@@ -48,6 +57,20 @@ type parserItem struct {
 	indentChar  rune
 	indentCount int
 
+	paramNames []string // names of parameters of the function, only these may be used as names of named parameters
+
+	silent    bool // output of the function is discarded
+	trimSpace bool // whitespaces following the item are removed
+
+	// lazy function parameters are captured unevaluated and evaluated by the function itself when needed
+	lazy       bool
+	rawDepth   int  // nesting level of items inside currently captured lazy parameter
+	rawEscaped bool // whether the previous captured rune was an escape character
+	rawStarted bool // whether the previous captured rune started a nested item (or was trimChar following the start)
+	rawTrim    bool // whether the previous captured rune was trimChar following the start of a nested item
+	rawBlock   rune // commentChar or verbatimChar if comment or verbatim block is being captured, 0 otherwise
+	rawHeld    bool // whether the previous captured rune was the block char, which may end the block
+
 	parent *parserItem
 }
 
@@ -67,6 +90,8 @@ func newParserItem(r rune, parent *parserItem, indentChar rune, indentCount int)
 			value:      "",
 			isVariable: true,
 		}}
+	} else if r == varStartChar {
+		item.t = itemTypeVariable
 	} else if r != 0 {
 		// if r == 0 do not add it to the name, otherwise we would create documents with NULL bytes inside!
 		item.name = string(r)
@@ -82,11 +107,31 @@ func (i *parserItem) IsString() bool {
 	return i != nil && i.t == itemTypeString
 }
 
+func (i *parserItem) IsVariable() bool {
+	return i != nil && i.t == itemTypeVariable
+}
+
+// ResolveVariableShorthand changes variable item to a static string starting with varStartChar,
+// if its name is not a valid variable name, so values like bcrypt hashes (e.g. <$2a$10$...>) are not affected
+func (i *parserItem) ResolveVariableShorthand() {
+	if !i.IsVariable() || variableNameRegexp.MatchString(i.name) {
+		return
+	}
+	i.t = itemTypeString
+	i.name = string(varStartChar) + i.name
+}
+
 func (i *parserItem) ProcessCurrentFunctionSection(r rune) (bool, error) {
 	if !i.IsFunction() {
 		return false, nil
 	}
 
+	// silentChar is allowed only as the first character of the function name
+	if r == silentChar && i.currSection == itemSectionName && i.name == "" && !i.silent {
+		i.silent = true
+		return true, nil
+	}
+
 	switch r {
 	case paramStartChar:
 		if i.currSection != itemSectionName {
@@ -136,38 +181,134 @@ func (i *parserItem) AddRune(r rune) error {
 	return nil
 }
 
+// IsCapturingRawParameters returns true if parameters of a lazy function are being captured
+func (i *parserItem) IsCapturingRawParameters() bool {
+	return i.IsFunction() && i.lazy && i.currSection == itemSectionParameters
+}
+
+// CaptureRawParameterRune adds rune to the current parameter without any processing of nested items,
+// only nesting level and escaping are tracked to find where the parameter ends
+func (i *parserItem) CaptureRawParameterRune(r rune) error {
+	if len(i.parameters) < i.currParam+1 {
+		i.parameters = append(i.parameters, itemParam{
+			value:      "",
+			isVariable: true,
+		})
+	}
+	param := &i.parameters[i.currParam]
+
+	started, trim := i.rawStarted, i.rawTrim
+	i.rawStarted, i.rawTrim = false, false
+
+	switch {
+	case i.rawBlock != 0:
+		// nesting and escaping is not tracked inside comment and verbatim blocks
+		if i.rawHeld && r == itemEndChar {
+			i.rawBlock = 0
+			i.rawDepth--
+		}
+		i.rawHeld = i.rawBlock != 0 && r == i.rawBlock
+	case started && (r == commentChar || r == verbatimChar):
+		i.rawBlock = r
+	case started && !trim && r == trimChar:
+		i.rawStarted, i.rawTrim = true, true
+	case i.rawEscaped:
+		i.rawEscaped = false
+	case r == escapeChar:
+		i.rawEscaped = true
+	case r == itemStartChar:
+		i.rawDepth++
+		i.rawStarted = true
+		param.isVariable = false
+	case r == itemEndChar:
+		if i.rawDepth == 0 {
+			return errors.New("missing closing brace")
+		}
+		i.rawDepth--
+	case i.rawDepth > 0:
+		// inside a nested item, nothing else to track
+	case r == paramSepChar:
+		i.currParam++
+		return nil
+	case r == paramEndChar:
+		i.currSection = itemSectionModifiers
+		return nil
+	case param.value == "" && (r == ' ' || r == '\t' || r == newlineChar):
+		return nil // eat whitespaces at the beginning of the parameter
+	}
+
+	param.value += string(r)
+	return nil
+}
+
+// GetRawParameters returns captured parameters of a lazy function with spaces trimmed,
+// flag whether each of them is a plain variable reference and their names (empty for positional parameters)
+func (i *parserItem) GetRawParameters() ([]string, []bool, []string) {
+	if i.HasNoParameters() {
+		return []string{}, []bool{}, []string{}
+	}
+	params := make([]string, len(i.parameters))
+	variables := make([]bool, len(i.parameters))
+	names := make([]string, len(i.parameters))
+	for idx, param := range i.parameters {
+		params[idx] = strings.TrimSpace(param.value)
+		if name, value, ok := i.splitNamedParameter(params[idx]); ok {
+			names[idx], params[idx] = name, strings.TrimSpace(value)
+		}
+		variables[idx] = param.isVariable
+	}
+	return params, variables, names
+}
+
 // GetParameters returns plain parameters (with variable names not interpreted) with spaces correctly trimmed
 func (i *parserItem) GetParameters() []string {
 	params := make([]string, len(i.parameters))
 	for idx, param := range i.parameters {
 		if !param.isVariable {
 			params[idx] = param.value
-			continue
+		} else {
+			params[idx] = strings.TrimSpace(param.value)
+		}
+		if param.name != "" {
+			params[idx] = param.name + "=" + params[idx]
 		}
-		params[idx] = strings.TrimSpace(param.value)
 	}
 	return params
 }
 
 // GetInterpretedParameters returns parameters with variables interpreted
 // TODO(ms): find a better way than passing valueStore in
-func (i *parserItem) GetInterpretedParameters(valueStore map[string]string) ([]string, error) {
+// names of the parameters are returned as well (empty for positional parameters)
+func (i *parserItem) GetInterpretedParameters(valueStore map[string]string) ([]string, []string, error) {
+	if i.HasNoParameters() {
+		return []string{}, []string{}, nil
+	}
 	params := make([]string, len(i.parameters))
+	names := make([]string, len(i.parameters))
 	for idx, param := range i.parameters {
+		names[idx] = param.name
 		if !param.isVariable {
 			params[idx] = param.value
 			continue
 		}
 
 		value := strings.TrimSpace(param.value)
+		if name, variable, ok := i.splitNamedParameter(value); ok && param.name == "" {
+			names[idx], value = name, strings.TrimSpace(variable)
+		}
 		val, found := valueStore[value]
 		if !found {
-			return nil, fmt.Errorf("variable [%s] not found", value)
+			return nil, nil, fmt.Errorf("variable [%s] not found", value)
 		}
 
 		params[idx] = val
 	}
-	return params, nil
+	return params, names, nil
+}
+
+// HasNoParameters returns true if function was called without any parameters, e.g. `<@generateULID()>`
+func (i *parserItem) HasNoParameters() bool {
+	return len(i.parameters) == 1 && i.parameters[0].isVariable && strings.TrimSpace(i.parameters[0].value) == ""
 }
 
 // GetModifiers returns modifiers with spaces trimmed
@@ -199,6 +340,72 @@ func (i *parserItem) addToParameter(r rune) {
 	i.parameters[i.currParam].value += string(r)
 }
 
+// AddVerbatimRune adds rune from a verbatim block to the name of a string or to the current parameter of a function,
+// parameter containing verbatim content is never considered to be a variable
+func (i *parserItem) AddVerbatimRune(r rune) {
+	if !i.IsFunction() || i.currSection != itemSectionParameters {
+		i.name += string(r)
+		return
+	}
+	i.addToStaticParameter(string(r))
+}
+
+// AddItemOutput adds output of a nested item to the name of a string or to the current parameter of a function
+func (i *parserItem) AddItemOutput(out string) {
+	if !i.IsFunction() {
+		i.name += out
+		return
+	}
+	i.addToStaticParameter(out)
+}
+
+// adds content to current parameter, which is then never considered to be a variable
+// if the parameter contains only name followed by = (e.g. `min=<1>`), the name is assigned to the parameter
+func (i *parserItem) addToStaticParameter(content string) {
+	param := i.currentParameter()
+	if param.isVariable && param.name == "" {
+		if name, value, ok := i.splitNamedParameter(param.value); ok && strings.TrimSpace(value) == "" {
+			param.name, param.value = name, ""
+		}
+	}
+	param.value += content
+	param.isVariable = false
+}
+
+// TrimTrailingSpace removes trailing whitespaces from the name of a string or from the current parameter of a function
+func (i *parserItem) TrimTrailingSpace() {
+	if !i.IsFunction() || i.currSection != itemSectionParameters {
+		i.name = strings.TrimRight(i.name, whitespaceSet)
+		return
+	}
+	if len(i.parameters) >= i.currParam+1 {
+		i.parameters[i.currParam].value = strings.TrimRight(i.parameters[i.currParam].value, whitespaceSet)
+	}
+}
+
+// returns current parameter of a function, the parameter is initialized if nothing was added to it yet
+// (e.g. nested item directly following a comma)
+func (i *parserItem) currentParameter() *itemParam {
+	if len(i.parameters) < i.currParam+1 {
+		i.parameters = append(i.parameters, itemParam{
+			value:      "",
+			isVariable: true,
+		})
+	}
+	return &i.parameters[i.currParam]
+}
+
+// splits parameter in format name=value to its name and value, ok is false if the parameter does not start
+// with a name of one of the function parameters (e.g. API_KEY=<...> is kept as a text)
+func (i *parserItem) splitNamedParameter(param string) (name, value string, ok bool) {
+	name, value, found := strings.Cut(param, "=")
+	name = strings.TrimSpace(name)
+	if !found || !slices.Contains(i.paramNames, name) {
+		return "", param, false
+	}
+	return name, value, true
+}
+
 // adds rune to current modifier
 func (i *parserItem) addToModifier(r rune) {
 	// this prevents issues with spaces between function closing parentheses and first |
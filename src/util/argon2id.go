@@ -12,6 +12,13 @@ import (
 	"golang.org/x/crypto/argon2"
 )
 
+// limits of hash parameters accepted by Argon2IDPasswordVerify, so a crafted hash cannot exhaust the parser service
+const (
+	argon2idMaxMemory      = 256 * 1024 // KiB
+	argon2idMaxIterations  = 16
+	argon2idMaxParallelism = 16
+)
+
 // Argon2idConfig provides an easier structure for Argon2id password hashing.
 //
 // The main gist (not 100% accurate, but close enough) is: complexity = memory * iterations / parallelism.
@@ -78,6 +85,15 @@ func Argon2IDPasswordVerify(hash, plain string) error {
 	if err != nil {
 		return err
 	}
+	if time < 1 || time > argon2idMaxIterations {
+		return fmt.Errorf("invalid hash, iterations t=%d must be between [1] and [%d]", time, argon2idMaxIterations)
+	}
+	if threads < 1 || threads > argon2idMaxParallelism {
+		return fmt.Errorf("invalid hash, parallelism p=%d must be between [1] and [%d]", threads, argon2idMaxParallelism)
+	}
+	if memory > argon2idMaxMemory {
+		return fmt.Errorf("invalid hash, memory m=%d must be at most [%d]", memory, argon2idMaxMemory)
+	}
 
 	salt, err := base64.RawStdEncoding.DecodeString(hashParts[4])
 	if err != nil {
@@ -90,6 +106,9 @@ func Argon2IDPasswordVerify(hash, plain string) error {
 	}
 
 	hashLen := len(decodedHash)
+	if hashLen < minPasswordDigestLen {
+		return fmt.Errorf("invalid hash, digest must be at least [%d] bytes long, found [%d]", minPasswordDigestLen, hashLen)
+	}
 	if hashLen > math.MaxUint32 {
 		return fmt.Errorf("invalid decoded hash length %d, exceeds max value for uint32", hashLen)
 	}
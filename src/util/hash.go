@@ -0,0 +1,51 @@
+package util
+
+import (
+	"crypto/md5"  //nolint:gosec // md5 is provided for legacy systems that require it
+	"crypto/sha1" //nolint:gosec // sha1 is provided for legacy systems that require it
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+// minimal length of decoded digest accepted by password hash verification (bytes),
+// shorter digests would match (almost) any password
+const minPasswordDigestLen = 16
+
+// HashNames returns names of all hash algorithms supported by HashFunc.
+func HashNames() []string {
+	return []string{"md5", "sha1", "sha224", "sha256", "sha384", "sha512", "sha3-256", "sha3-512", "blake2b"}
+}
+
+// HashFunc returns constructor of a cryptographic hash algorithm identified by its name.
+func HashFunc(name string) (func() hash.Hash, error) {
+	switch name {
+	case "md5":
+		return md5.New, nil
+	case "sha1":
+		return sha1.New, nil
+	case "sha224":
+		return sha256.New224, nil
+	case "sha256":
+		return sha256.New, nil
+	case "sha384":
+		return sha512.New384, nil
+	case "sha512":
+		return sha512.New, nil
+	case "sha3-256":
+		return sha3.New256, nil
+	case "sha3-512":
+		return sha3.New512, nil
+	case "blake2b":
+		return func() hash.Hash {
+			h, _ := blake2b.New512(nil) // error is returned only for keys longer than 64 bytes
+			return h
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported hash algorithm [%s], supported: [%s]", name, strings.Join(HashNames(), ", "))
+}
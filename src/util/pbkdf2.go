@@ -0,0 +1,81 @@
+package util
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	pbkdf2SHA256Algorithm = "pbkdf2_sha256"
+	pbkdf2MaxIterations   = 2_000_000 // limit accepted by PBKDF2SHA256PasswordVerify, so a crafted hash cannot exhaust the parser service
+)
+
+// PBKDF2Config provides an easier structure for PBKDF2-SHA256 password hashing.
+type PBKDF2Config struct {
+	Iterations int
+
+	SaltLen int // characters, salt is comprised of `[a-zA-Z0-9_-.]` character set
+	KeyLen  int // bytes
+}
+
+// DefaultPBKDF2Conf provides parameters recommended by OWASP for PBKDF2-HMAC-SHA256.
+func DefaultPBKDF2Conf() PBKDF2Config {
+	return PBKDF2Config{
+		Iterations: 600_000,
+
+		SaltLen: 22,
+		KeyLen:  32,
+	}
+}
+
+// PBKDF2SHA256PasswordHash returns PBKDF2-SHA256 hash in Django format `pbkdf2_sha256$iterations$salt$hash`.
+func PBKDF2SHA256PasswordHash(plain string, conf PBKDF2Config) (string, error) {
+	salt, err := RandString(conf.SaltLen)
+	if err != nil {
+		return "", err
+	}
+
+	hash := pbkdf2.Key([]byte(plain), []byte(salt), conf.Iterations, conf.KeyLen, sha256.New)
+
+	return fmt.Sprintf("%s$%d$%s$%s", pbkdf2SHA256Algorithm, conf.Iterations, salt, base64.StdEncoding.EncodeToString(hash)), nil
+}
+
+func PBKDF2SHA256PasswordVerify(hash, plain string) error {
+	hashParts := strings.Split(hash, "$")
+	if len(hashParts) != 4 {
+		return fmt.Errorf("invalid hash, expected [4] parts found [%d]", len(hashParts))
+	}
+
+	if hashParts[0] != pbkdf2SHA256Algorithm {
+		return fmt.Errorf("invalid hash, expected [%s] algorithm found [%s]", pbkdf2SHA256Algorithm, hashParts[0])
+	}
+
+	iterations, err := strconv.Atoi(hashParts[1])
+	if err != nil {
+		return err
+	}
+	if iterations < 1 || iterations > pbkdf2MaxIterations {
+		return fmt.Errorf("invalid hash, iterations [%d] must be between [1] and [%d]", iterations, pbkdf2MaxIterations)
+	}
+
+	decodedHash, err := base64.StdEncoding.DecodeString(hashParts[3])
+	if err != nil {
+		return err
+	}
+	if len(decodedHash) < minPasswordDigestLen {
+		return fmt.Errorf("invalid hash, digest must be at least [%d] bytes long, found [%d]", minPasswordDigestLen, len(decodedHash))
+	}
+
+	hashToCompare := pbkdf2.Key([]byte(plain), []byte(hashParts[2]), iterations, len(decodedHash), sha256.New)
+	if subtle.ConstantTimeCompare(decodedHash, hashToCompare) != 1 {
+		return errors.New("hashedPassword is not the hash of the given password")
+	}
+	return nil
+}
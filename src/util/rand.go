@@ -1,6 +1,11 @@
 package util
 
-import "math"
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+)
 
 const (
 	randStringChars      = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-."
@@ -17,3 +22,24 @@ func BytesToString(in []byte) string {
 	}
 	return string(in)
 }
+
+// RandString generates cryptographically secure random string comprised of `[a-zA-Z0-9_-.]` character set
+func RandString(length int) (string, error) {
+	b := make([]byte, length)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return BytesToString(b), nil
+}
+
+// RandInt returns cryptographically secure uniform random int in [0, n)
+func RandInt(n int) (int, error) {
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid upper bound [%d], must be positive", n)
+	}
+	num, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(num.Int64()), nil
+}
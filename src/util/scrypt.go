@@ -0,0 +1,111 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// limits of hash parameters accepted by ScryptPasswordVerify, so a crafted hash cannot exhaust the parser service
+const (
+	scryptMaxLogN        = 20
+	scryptMaxMemory      = 1 << 30 // bytes
+	scryptMaxParallelism = 16
+)
+
+// ScryptConfig provides an easier structure for scrypt password hashing.
+//
+// CPU/memory cost is 2^LogN, memory usage is roughly 128 * 2^LogN * BlockSize bytes.
+type ScryptConfig struct {
+	LogN        uint8 // log2 of CPU/memory cost parameter N
+	BlockSize   int   // r parameter
+	Parallelism int   // p parameter
+
+	SaltLen int // bytes
+	KeyLen  int // bytes
+}
+
+// DefaultScryptConf provides standard sane parameters chosen to not overload the parser service.
+func DefaultScryptConf() ScryptConfig {
+	return ScryptConfig{
+		LogN:        15, // N = 32768, 32MiB of memory with r = 8
+		BlockSize:   8,
+		Parallelism: 1,
+
+		SaltLen: 16,
+		KeyLen:  32,
+	}
+}
+
+// ScryptPasswordHash returns scrypt hash in PHC string format `$scrypt$ln=15,r=8,p=1$salt$hash`.
+func ScryptPasswordHash(plain string, conf ScryptConfig) (string, error) {
+	salt := make([]byte, conf.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	hash, err := scrypt.Key([]byte(plain), salt, 1<<conf.LogN, conf.BlockSize, conf.Parallelism, conf.KeyLen)
+	if err != nil {
+		return "", err
+	}
+
+	b64Salt := base64.RawStdEncoding.EncodeToString(salt)
+	b64Hash := base64.RawStdEncoding.EncodeToString(hash)
+
+	return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s", conf.LogN, conf.BlockSize, conf.Parallelism, b64Salt, b64Hash), nil
+}
+
+func ScryptPasswordVerify(hash, plain string) error {
+	var logN uint8
+	var blockSize int
+	var parallelism int
+
+	hashParts := strings.Split(hash, "$")
+	if len(hashParts) != 5 {
+		return fmt.Errorf("invalid hash, expected [5] parts found [%d]", len(hashParts))
+	}
+
+	if hashParts[1] != "scrypt" {
+		return fmt.Errorf("invalid hash, expected [scrypt] algorithm found [%s]", hashParts[1])
+	}
+
+	if _, err := fmt.Sscanf(hashParts[2], "ln=%d,r=%d,p=%d", &logN, &blockSize, &parallelism); err != nil {
+		return err
+	}
+	if logN > scryptMaxLogN {
+		return fmt.Errorf("invalid hash, cost parameter ln=%d must be at most [%d]", logN, scryptMaxLogN)
+	}
+	if blockSize < 1 || blockSize > scryptMaxMemory/128>>logN {
+		return fmt.Errorf("invalid hash, block size r=%d must be positive and use at most [%d] bytes of memory", blockSize, scryptMaxMemory)
+	}
+	if parallelism < 1 || parallelism > scryptMaxParallelism {
+		return fmt.Errorf("invalid hash, parallelism p=%d must be between [1] and [%d]", parallelism, scryptMaxParallelism)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(hashParts[3])
+	if err != nil {
+		return err
+	}
+
+	decodedHash, err := base64.RawStdEncoding.DecodeString(hashParts[4])
+	if err != nil {
+		return err
+	}
+	if len(decodedHash) < minPasswordDigestLen {
+		return fmt.Errorf("invalid hash, digest must be at least [%d] bytes long, found [%d]", minPasswordDigestLen, len(decodedHash))
+	}
+
+	hashToCompare, err := scrypt.Key([]byte(plain), salt, 1<<logN, blockSize, parallelism, len(decodedHash))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(decodedHash, hashToCompare) != 1 {
+		return errors.New("hashedPassword is not the hash of the given password")
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"math/big"
+)
+
+// ED25519PublicKeyToX25519 converts ed25519 public key into X25519 public key usable for encryption.
+// Conversion is the same as libsodium's crypto_sign_ed25519_pk_to_curve25519 (u = (1 + y) / (1 - y) mod p).
+func ED25519PublicKeyToX25519(pk ed25519.PublicKey) ([]byte, error) {
+	if len(pk) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid ed25519 public key length")
+	}
+
+	p := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+	// y is encoded in little-endian with the highest bit used as a sign of x
+	yBytes := make([]byte, ed25519.PublicKeySize)
+	for i, b := range pk {
+		yBytes[ed25519.PublicKeySize-1-i] = b
+	}
+	yBytes[0] &= 0x7f
+	y := new(big.Int).SetBytes(yBytes)
+
+	denominator := new(big.Int).Sub(big.NewInt(1), y)
+	denominator.Mod(denominator, p)
+	if denominator.Sign() == 0 {
+		return nil, errors.New("invalid ed25519 public key")
+	}
+	u := new(big.Int).Add(big.NewInt(1), y)
+	u.Mul(u, denominator.ModInverse(denominator, p))
+	u.Mod(u, p)
+
+	uBytes := u.FillBytes(make([]byte, 32))
+	out := make([]byte, 32)
+	for i, b := range uBytes {
+		out[31-i] = b
+	}
+	return out, nil
+}